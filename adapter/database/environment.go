@@ -0,0 +1,183 @@
+// Package database provides CRUD operations with database.
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/database"
+)
+
+const (
+	environmentTable       = "environment"
+	environmentTableFields = "id, name, variables, created_at, updated_at"
+)
+
+// EnvironmentRepository object capable of interacting with EnvironmentRepository.
+type EnvironmentRepository struct {
+	db  *database.Database
+	ctx context.Context
+}
+
+// NewEnvironmentRepository creates a new EnvironmentRepository.
+func NewEnvironmentRepository(ctx context.Context, db *database.Database) *EnvironmentRepository {
+	return &EnvironmentRepository{
+		db:  db,
+		ctx: ctx,
+	}
+}
+
+type environmentDTO struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Variables string    `db:"variables"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func newEnvironmentDTO(in *entity.Environment) (*environmentDTO, error) {
+	dto := &environmentDTO{
+		ID:        in.ID,
+		Name:      in.Name,
+		Variables: "{}",
+	}
+
+	if in.Variables != nil {
+		data, err := json.Marshal(in.Variables)
+		if err != nil {
+			return nil, err
+		}
+		dto.Variables = string(data)
+	}
+
+	return dto, nil
+}
+
+func (dto *environmentDTO) entity() (*entity.Environment, error) {
+	out := &entity.Environment{
+		ID:        dto.ID,
+		Name:      dto.Name,
+		Variables: make(map[string]string),
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(dto.Variables), &out.Variables); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Get returns all environments.
+func (repo *EnvironmentRepository) Get() ([]*entity.Environment, error) {
+	var dto []*environmentDTO
+
+	err := repo.db.Connector.SelectContext(repo.ctx, &dto, fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		ORDER BY name;`, environmentTableFields, environmentTable))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entity.Environment, 0, len(dto))
+	for _, d := range dto {
+		e, err := d.entity()
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, e)
+	}
+
+	return resp, nil
+}
+
+// GetByID returns environment by id.
+func (repo *EnvironmentRepository) GetByID(id int64) (*entity.Environment, error) {
+	var dto []*environmentDTO
+
+	err := repo.db.Connector.SelectContext(repo.ctx, &dto, fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE id = ?`, environmentTableFields, environmentTable), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(dto) == 0 {
+		return nil, entity.ErrEnvironmentNotExists
+	}
+
+	return dto[0].entity()
+}
+
+// Create creates new environment.
+func (repo *EnvironmentRepository) Create(in *entity.Environment) (*entity.Environment, error) {
+	dto, err := newEnvironmentDTO(in)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := repo.db.Connector.BindNamed(fmt.Sprintf(`
+			INSERT INTO %s (name, variables)
+			VALUES (:name, :variables)
+			RETURNING %s;`, environmentTable, environmentTableFields), dto)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.db.Connector.GetContext(repo.ctx, dto, query, args...); err != nil {
+		return nil, err
+	}
+
+	return dto.entity()
+}
+
+// Update updates environment.
+func (repo *EnvironmentRepository) Update(in *entity.Environment) (*entity.Environment, error) {
+	attrs := make([]string, 0, 2)
+	mapper := make(map[string]interface{}, 3)
+
+	if len(in.Name) > 0 {
+		attrs = append(attrs, "name = :name")
+		mapper["name"] = in.Name
+	}
+	if in.Variables != nil {
+		data, err := json.Marshal(in.Variables)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, "variables = :variables")
+		mapper["variables"] = string(data)
+	}
+	if len(attrs) == 0 {
+		return repo.GetByID(in.ID)
+	}
+
+	mapper["id"] = in.ID
+
+	dto := &environmentDTO{}
+	query, args, err := repo.db.Connector.BindNamed(fmt.Sprintf(`
+			UPDATE %s SET %s, updated_at = datetime('now','localtime')
+			WHERE id = :id
+			RETURNING %s;`, environmentTable, strings.Join(attrs, ","), environmentTableFields), mapper)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.db.Connector.GetContext(repo.ctx, dto, query, args...); err != nil {
+		return nil, err
+	}
+
+	return dto.entity()
+}
+
+// Delete deletes environment.
+func (repo *EnvironmentRepository) Delete(id int64) error {
+	_, err := repo.db.Connector.NamedExecContext(repo.ctx, fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE id = :id;`, environmentTable), &environmentDTO{ID: id})
+	return err
+}
@@ -0,0 +1,273 @@
+// Package database provides CRUD operations with database.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/database"
+	"github.com/forest33/warthog/pkg/database/types"
+)
+
+const (
+	historyTable       = "history"
+	historyTableFields = "id, server_id, service, method, data, input, metadata, response, header, trailer, status_code, status, message, spent_time, created_at"
+)
+
+// HistoryRepository object capable of interacting with HistoryRepository.
+type HistoryRepository struct {
+	db  *database.Database
+	ctx context.Context
+}
+
+// NewHistoryRepository creates a new HistoryRepository.
+func NewHistoryRepository(ctx context.Context, db *database.Database) *HistoryRepository {
+	return &HistoryRepository{
+		db:  db,
+		ctx: ctx,
+	}
+}
+
+type historyDTO struct {
+	ID          int64          `db:"id"`
+	ServerID    int64          `db:"server_id"`
+	ServerTitle sql.NullString `db:"server_title"`
+	Service     string         `db:"service"`
+	Method      string         `db:"method"`
+	Data        string         `db:"data"`
+	Input       sql.NullString `db:"input"`
+	Metadata    string         `db:"metadata"`
+	Response    string         `db:"response"`
+	Header      string         `db:"header"`
+	Trailer     string         `db:"trailer"`
+	StatusCode  uint32         `db:"status_code"`
+	Status      string         `db:"status"`
+	Message     string         `db:"message"`
+	SpentTime   string         `db:"spent_time"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+func newHistoryDTO(in *entity.History) (*historyDTO, error) {
+	dto := &historyDTO{
+		ID:         in.ID,
+		ServerID:   in.ServerID,
+		Service:    in.Service,
+		Method:     in.Method,
+		Response:   in.Response,
+		StatusCode: in.Code,
+		Status:     in.Status,
+		Message:    in.Message,
+		SpentTime:  in.SpentTime,
+	}
+
+	var err error
+	if dto.Data, err = marshalJSON(in.Data, "{}"); err != nil {
+		return nil, err
+	}
+	if dto.Metadata, err = marshalJSON(in.Metadata, "[]"); err != nil {
+		return nil, err
+	}
+	if dto.Header, err = marshalJSON(in.Header, "{}"); err != nil {
+		return nil, err
+	}
+	if dto.Trailer, err = marshalJSON(in.Trailer, "{}"); err != nil {
+		return nil, err
+	}
+	if in.Input != nil {
+		input, err := json.Marshal(in.Input)
+		if err != nil {
+			return nil, err
+		}
+		dto.Input = types.StringToSQL(string(input))
+	}
+
+	return dto, nil
+}
+
+func (dto *historyDTO) entity() (*entity.History, error) {
+	out := &entity.History{
+		ID:          dto.ID,
+		ServerID:    dto.ServerID,
+		ServerTitle: dto.ServerTitle.String,
+		Service:     dto.Service,
+		Method:      dto.Method,
+		Response:    dto.Response,
+		Code:        dto.StatusCode,
+		Status:      dto.Status,
+		Message:     dto.Message,
+		SpentTime:   dto.SpentTime,
+		CreatedAt:   dto.CreatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(dto.Data), &out.Data); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(dto.Metadata), &out.Metadata); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(dto.Header), &out.Header); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(dto.Trailer), &out.Trailer); err != nil {
+		return nil, err
+	}
+	if dto.Input.Valid {
+		if err := json.Unmarshal([]byte(dto.Input.String), &out.Input); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Get returns history entries matching the filter, newest first.
+func (repo *HistoryRepository) Get(filter *entity.HistoryFilter) ([]*entity.History, error) {
+	var (
+		dto   []*historyDTO
+		where = make([]string, 0, 5)
+		args  = make([]interface{}, 0, 7)
+	)
+
+	if filter.ServerID != 0 {
+		where = append(where, "h.server_id = ?")
+		args = append(args, filter.ServerID)
+	}
+	if filter.Service != "" {
+		where = append(where, "h.service = ?")
+		args = append(args, filter.Service)
+	}
+	if filter.Method != "" {
+		where = append(where, "h.method = ?")
+		args = append(args, filter.Method)
+	}
+	if filter.Status != "" {
+		where = append(where, "h.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Search != "" {
+		where = append(where, "(h.service LIKE ? OR h.method LIKE ? OR h.data LIKE ? OR h.response LIKE ?)")
+		search := "%" + filter.Search + "%"
+		args = append(args, search, search, search, search)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, w.title AS server_title
+		FROM %s h
+		LEFT JOIN %s w ON w.id = h.server_id`, prefixFields("h", historyTableFields), historyTable, workspaceTable)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY h.id DESC LIMIT ? OFFSET ?;"
+	args = append(args, filter.Limit, filter.Offset)
+
+	if err := repo.db.Connector.SelectContext(repo.ctx, &dto, query, args...); err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entity.History, 0, len(dto))
+	for _, d := range dto {
+		h, err := d.entity()
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, h)
+	}
+
+	return resp, nil
+}
+
+// GetByID returns history entry by id.
+func (repo *HistoryRepository) GetByID(id int64) (*entity.History, error) {
+	var dto []*historyDTO
+
+	err := repo.db.Connector.SelectContext(repo.ctx, &dto, fmt.Sprintf(`
+		SELECT %s, w.title AS server_title
+		FROM %s h
+		LEFT JOIN %s w ON w.id = h.server_id
+		WHERE h.id = ?`, prefixFields("h", historyTableFields), historyTable, workspaceTable), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(dto) == 0 {
+		return nil, entity.ErrHistoryNotExists
+	}
+
+	return dto[0].entity()
+}
+
+// Create creates new history entry.
+func (repo *HistoryRepository) Create(in *entity.History) (*entity.History, error) {
+	dto, err := newHistoryDTO(in)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := repo.db.Connector.BindNamed(fmt.Sprintf(`
+			INSERT INTO %s (server_id, service, method, data, input, metadata, response, header, trailer, status_code, status, message, spent_time)
+			VALUES (:server_id, :service, :method, :data, :input, :metadata, :response, :header, :trailer, :status_code, :status, :message, :spent_time)
+			RETURNING %s;`, historyTable, historyTableFields), dto)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.db.Connector.GetContext(repo.ctx, dto, query, args...); err != nil {
+		return nil, err
+	}
+
+	return dto.entity()
+}
+
+// Delete deletes history entry.
+func (repo *HistoryRepository) Delete(id int64) error {
+	_, err := repo.db.Connector.ExecContext(repo.ctx, fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE id = ?;`, historyTable), id)
+	return err
+}
+
+// Clear deletes all history entries of the server, or all entries if the server id is 0.
+func (repo *HistoryRepository) Clear(serverID int64) error {
+	if serverID == 0 {
+		_, err := repo.db.Connector.ExecContext(repo.ctx, fmt.Sprintf("DELETE FROM %s;", historyTable))
+		return err
+	}
+
+	_, err := repo.db.Connector.ExecContext(repo.ctx, fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE server_id = ?;`, historyTable), serverID)
+	return err
+}
+
+// Truncate keeps only the given number of the newest history entries.
+func (repo *HistoryRepository) Truncate(keep int) error {
+	_, err := repo.db.Connector.ExecContext(repo.ctx, fmt.Sprintf(`
+			DELETE FROM %[1]s
+			WHERE id NOT IN (SELECT id FROM %[1]s ORDER BY id DESC LIMIT ?);`, historyTable), keep)
+	return err
+}
+
+func marshalJSON(v interface{}, empty string) (string, error) {
+	if v == nil {
+		return empty, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if string(data) == "null" {
+		return empty, nil
+	}
+	return string(data), nil
+}
+
+func prefixFields(prefix, fields string) string {
+	list := strings.Split(fields, ", ")
+	for i := range list {
+		list[i] = prefix + "." + list[i]
+	}
+	return strings.Join(list, ", ")
+}
@@ -14,7 +14,9 @@ const (
 	settingsTable       = "settings"
 	settingsTableFields = `window_width, window_height, window_x, window_y, single_instance, connect_timeout,
 							request_timeout, k8s_request_timeout, non_blocking_connection, sort_methods_by_name, max_loop_depth, 
-							emit_defaults, check_updates`
+							emit_defaults, check_updates, proxy_type, proxy_addr, proxy_login, proxy_password, cert_expiry_warning_days,
+							call_compression, call_max_recv_msg_size, call_max_send_msg_size, call_keepalive_time, call_keepalive_timeout,
+							call_keepalive_permit_without_stream, call_wait_for_ready, auto_reconnect, reconnect_base_delay, reconnect_max_delay`
 )
 
 // SettingsRepository object capable of interacting with SettingsRepository.
@@ -32,19 +34,34 @@ func NewSettingsRepository(ctx context.Context, db *database.Database) *Settings
 }
 
 type settingsDTO struct {
-	WindowWidth           int  `db:"window_width"`
-	WindowHeight          int  `db:"window_height"`
-	WindowX               int  `db:"window_x"`
-	WindowY               int  `db:"window_y"`
-	SingleInstance        bool `db:"single_instance"`
-	ConnectTimeout        int  `db:"connect_timeout"`
-	RequestTimeout        int  `db:"request_timeout"`
-	K8SRequestTimeout     int  `db:"k8s_request_timeout"`
-	NonBlockingConnection bool `db:"non_blocking_connection"`
-	SortMethodsByName     bool `db:"sort_methods_by_name"`
-	MaxLoopDepth          int  `db:"max_loop_depth"`
-	EmitDefaults          bool `db:"emit_defaults"`
-	CheckUpdates          bool `db:"check_updates"`
+	WindowWidth           int    `db:"window_width"`
+	WindowHeight          int    `db:"window_height"`
+	WindowX               int    `db:"window_x"`
+	WindowY               int    `db:"window_y"`
+	SingleInstance        bool   `db:"single_instance"`
+	ConnectTimeout        int    `db:"connect_timeout"`
+	RequestTimeout        int    `db:"request_timeout"`
+	K8SRequestTimeout     int    `db:"k8s_request_timeout"`
+	NonBlockingConnection bool   `db:"non_blocking_connection"`
+	SortMethodsByName     bool   `db:"sort_methods_by_name"`
+	MaxLoopDepth          int    `db:"max_loop_depth"`
+	EmitDefaults          bool   `db:"emit_defaults"`
+	CheckUpdates          bool   `db:"check_updates"`
+	ProxyType             string `db:"proxy_type"`
+	ProxyAddr             string `db:"proxy_addr"`
+	ProxyLogin            string `db:"proxy_login"`
+	ProxyPassword         string `db:"proxy_password"`
+	CertExpiryWarningDays int    `db:"cert_expiry_warning_days"`
+	CallCompression       string `db:"call_compression"`
+	CallMaxRecvMsgSize    int    `db:"call_max_recv_msg_size"`
+	CallMaxSendMsgSize    int    `db:"call_max_send_msg_size"`
+	CallKeepaliveTime     int    `db:"call_keepalive_time"`
+	CallKeepaliveTimeout  int    `db:"call_keepalive_timeout"`
+	CallKeepaliveNoStream bool   `db:"call_keepalive_permit_without_stream"`
+	CallWaitForReady      bool   `db:"call_wait_for_ready"`
+	AutoReconnect         bool   `db:"auto_reconnect"`
+	ReconnectBaseDelay    int    `db:"reconnect_base_delay"`
+	ReconnectMaxDelay     int    `db:"reconnect_max_delay"`
 }
 
 func (dto *settingsDTO) entity() *entity.Settings {
@@ -62,6 +79,25 @@ func (dto *settingsDTO) entity() *entity.Settings {
 		MaxLoopDepth:          &dto.MaxLoopDepth,
 		EmitDefaults:          &dto.EmitDefaults,
 		CheckUpdates:          &dto.CheckUpdates,
+		Proxy: &entity.Proxy{
+			Type:     dto.ProxyType,
+			Addr:     dto.ProxyAddr,
+			Login:    dto.ProxyLogin,
+			Password: dto.ProxyPassword,
+		},
+		CertExpiryWarningDays: &dto.CertExpiryWarningDays,
+		CallOptions: &entity.CallOptions{
+			Compression:                  dto.CallCompression,
+			MaxRecvMsgSize:               dto.CallMaxRecvMsgSize,
+			MaxSendMsgSize:               dto.CallMaxSendMsgSize,
+			KeepaliveTime:                dto.CallKeepaliveTime,
+			KeepaliveTimeout:             dto.CallKeepaliveTimeout,
+			KeepalivePermitWithoutStream: &dto.CallKeepaliveNoStream,
+			WaitForReady:                 &dto.CallWaitForReady,
+		},
+		AutoReconnect:      &dto.AutoReconnect,
+		ReconnectBaseDelay: &dto.ReconnectBaseDelay,
+		ReconnectMaxDelay:  &dto.ReconnectMaxDelay,
 	}
 }
 
@@ -80,8 +116,8 @@ func (repo *SettingsRepository) Get() (*entity.Settings, error) {
 // Update updates Settings.
 func (repo *SettingsRepository) Update(in *entity.Settings) (*entity.Settings, error) {
 	dto := &settingsDTO{}
-	attrs := make([]string, 0, 13)
-	mapper := make(map[string]interface{}, 13)
+	attrs := make([]string, 0, 28)
+	mapper := make(map[string]interface{}, 28)
 
 	if in.WindowWidth > 0 {
 		attrs = append(attrs, "window_width = :window_width")
@@ -135,6 +171,42 @@ func (repo *SettingsRepository) Update(in *entity.Settings) (*entity.Settings, e
 		attrs = append(attrs, "check_updates = :check_updates")
 		mapper["check_updates"] = in.CheckUpdates
 	}
+	if in.Proxy != nil {
+		attrs = append(attrs, "proxy_type = :proxy_type", "proxy_addr = :proxy_addr", "proxy_login = :proxy_login", "proxy_password = :proxy_password")
+		mapper["proxy_type"] = in.Proxy.Type
+		mapper["proxy_addr"] = in.Proxy.Addr
+		mapper["proxy_login"] = in.Proxy.Login
+		mapper["proxy_password"] = in.Proxy.Password
+	}
+	if in.CertExpiryWarningDays != nil {
+		attrs = append(attrs, "cert_expiry_warning_days = :cert_expiry_warning_days")
+		mapper["cert_expiry_warning_days"] = in.CertExpiryWarningDays
+	}
+	if in.CallOptions != nil {
+		attrs = append(attrs, "call_compression = :call_compression", "call_max_recv_msg_size = :call_max_recv_msg_size",
+			"call_max_send_msg_size = :call_max_send_msg_size", "call_keepalive_time = :call_keepalive_time",
+			"call_keepalive_timeout = :call_keepalive_timeout", "call_keepalive_permit_without_stream = :call_keepalive_permit_without_stream",
+			"call_wait_for_ready = :call_wait_for_ready")
+		mapper["call_compression"] = in.CallOptions.Compression
+		mapper["call_max_recv_msg_size"] = in.CallOptions.MaxRecvMsgSize
+		mapper["call_max_send_msg_size"] = in.CallOptions.MaxSendMsgSize
+		mapper["call_keepalive_time"] = in.CallOptions.KeepaliveTime
+		mapper["call_keepalive_timeout"] = in.CallOptions.KeepaliveTimeout
+		mapper["call_keepalive_permit_without_stream"] = in.CallOptions.KeepalivePermitWithoutStream != nil && *in.CallOptions.KeepalivePermitWithoutStream
+		mapper["call_wait_for_ready"] = in.CallOptions.IsWaitForReady()
+	}
+	if in.AutoReconnect != nil {
+		attrs = append(attrs, "auto_reconnect = :auto_reconnect")
+		mapper["auto_reconnect"] = in.AutoReconnect
+	}
+	if in.ReconnectBaseDelay != nil {
+		attrs = append(attrs, "reconnect_base_delay = :reconnect_base_delay")
+		mapper["reconnect_base_delay"] = in.ReconnectBaseDelay
+	}
+	if in.ReconnectMaxDelay != nil {
+		attrs = append(attrs, "reconnect_max_delay = :reconnect_max_delay")
+		mapper["reconnect_max_delay"] = in.ReconnectMaxDelay
+	}
 	if len(attrs) == 0 {
 		return repo.Get()
 	}
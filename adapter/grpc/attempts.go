@@ -0,0 +1,50 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/stats"
+)
+
+type attemptsKey struct{}
+
+// attemptsHandler counts the attempts of the calls made with the context from withAttempts,
+// the retried attempts are sent with the grpc-previous-rpc-attempts header, the transparent retries are not counted.
+type attemptsHandler struct{}
+
+// withAttempts returns the context counting the attempts of the call.
+func withAttempts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, new(atomic.Uint32))
+}
+
+// getAttempts returns the number of the attempts of the call made with the context.
+func getAttempts(ctx context.Context) uint {
+	if ctx == nil {
+		return 0
+	}
+	if n, ok := ctx.Value(attemptsKey{}).(*atomic.Uint32); ok {
+		return uint(n.Load())
+	}
+	return 0
+}
+
+func (attemptsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (attemptsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if begin, ok := s.(*stats.Begin); !ok || !begin.Client || begin.IsTransparentRetryAttempt {
+		return
+	}
+	if n, ok := ctx.Value(attemptsKey{}).(*atomic.Uint32); ok {
+		n.Add(1)
+	}
+}
+
+func (attemptsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (attemptsHandler) HandleConn(context.Context, stats.ConnStats) {}
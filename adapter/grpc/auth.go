@@ -8,6 +8,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/oauth"
 
@@ -23,6 +24,9 @@ type oauthToken struct {
 	token *oauth2.Token
 }
 
+// authHeaders returns the authentication headers of the HTTP request.
+type authHeaders func(ctx context.Context) (map[string]string, error)
+
 var symmetricAlgorithms = map[string]struct{}{
 	"HS256": {},
 	"HS384": {},
@@ -67,9 +71,21 @@ func (c *Client) authBearer(auth *entity.Auth) (grpc.DialOption, error) {
 }
 
 func (c *Client) authJWT(auth *entity.Auth) (grpc.DialOption, error) {
+	token, err := signJWT(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.authBearer(&entity.Auth{
+		Token:        token,
+		HeaderPrefix: auth.HeaderPrefix,
+	})
+}
+
+func signJWT(auth *entity.Auth) (string, error) {
 	signingMethod := jwt.GetSigningMethod(auth.Algorithm)
 	if signingMethod == nil {
-		return nil, fmt.Errorf("unknown signing algorithm: %s", auth.Algorithm)
+		return "", fmt.Errorf("unknown signing algorithm: %s", auth.Algorithm)
 	}
 
 	var (
@@ -82,26 +98,17 @@ func (c *Client) authJWT(auth *entity.Auth) (grpc.DialOption, error) {
 		if auth.SecretBase64 {
 			secret, err = base64.StdEncoding.DecodeString(auth.Secret)
 			if err != nil {
-				return nil, err
+				return "", err
 			}
 		}
 	} else {
 		secret, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(auth.PrivateKey))
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 	}
 
-	jwtToken := jwt.NewWithClaims(signingMethod, jwt.MapClaims(auth.Payload))
-	token, err := jwtToken.SignedString(secret)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.authBearer(&entity.Auth{
-		Token:        token,
-		HeaderPrefix: auth.HeaderPrefix,
-	})
+	return jwt.NewWithClaims(signingMethod, jwt.MapClaims(auth.Payload)).SignedString(secret)
 }
 
 func (c *Client) authGCE(auth *entity.Auth) (grpc.DialOption, error) {
@@ -112,6 +119,51 @@ func (c *Client) authGCE(auth *entity.Auth) (grpc.DialOption, error) {
 	return grpc.WithPerRPCCredentials(perRPC), nil
 }
 
+// getAuthHeaders returns the authentication headers for the gRPC-Web and Connect transports,
+// the per-RPC credentials of gRPC are not used because they require the gRPC connection.
+func getAuthHeaders(auth *entity.Auth) (authHeaders, error) {
+	if auth == nil || auth.Type == entity.AuthTypeNone {
+		return nil, nil
+	}
+
+	switch auth.Type {
+	case entity.AuthTypeBasic:
+		b := basicAuth{login: auth.Login, password: auth.Password}
+		return func(ctx context.Context) (map[string]string, error) {
+			return b.GetRequestMetadata(ctx)
+		}, nil
+	case entity.AuthTypeBearer:
+		return tokenHeaders(oauthToken{token: &oauth2.Token{AccessToken: auth.Token, TokenType: auth.HeaderPrefix}}), nil
+	case entity.AuthTypeJWT:
+		token, err := signJWT(auth)
+		if err != nil {
+			return nil, err
+		}
+		return tokenHeaders(oauthToken{token: &oauth2.Token{AccessToken: token, TokenType: auth.HeaderPrefix}}), nil
+	case entity.AuthTypeGCE:
+		cfg, err := google.JWTConfigFromJSON([]byte(auth.GoogleToken), auth.GoogleScopes...)
+		if err != nil {
+			return nil, err
+		}
+		return tokenHeaders(cfg.TokenSource(context.Background())), nil
+	}
+
+	return nil, nil
+}
+
+func tokenHeaders(ts oauth2.TokenSource) authHeaders {
+	ts = oauth2.ReuseTokenSource(nil, ts)
+	return func(_ context.Context) (map[string]string, error) {
+		token, err := ts.Token()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{
+			"authorization": token.Type() + " " + token.AccessToken,
+		}, nil
+	}
+}
+
 func (b basicAuth) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
 	auth := b.login + ":" + b.password
 	enc := base64.StdEncoding.EncodeToString([]byte(auth))
@@ -0,0 +1,72 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+// getCallSettings returns the call options of the server with the defaults from the settings.
+func (c *Client) getCallSettings() *entity.CallOptions {
+	opts := c.options()
+	if settings := c.settings(); settings != nil {
+		return opts.callOptions.Merge(settings.CallOptions)
+	}
+	return opts.callOptions.Merge(nil)
+}
+
+// getCallDialOptions returns the keepalive parameters and the message size limits and wait for ready of all calls of the connection.
+func (c *Client) getCallDialOptions() []grpc.DialOption {
+	o := c.getCallSettings()
+
+	var (
+		dialOptions []grpc.DialOption
+		callOptions []grpc.CallOption
+	)
+
+	if o.IsKeepaliveEnabled() {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                time.Duration(o.KeepaliveTime) * time.Second,
+			Timeout:             time.Duration(o.KeepaliveTimeout) * time.Second,
+			PermitWithoutStream: o.KeepalivePermitWithoutStream != nil && *o.KeepalivePermitWithoutStream,
+		}))
+	}
+
+	if o.MaxRecvMsgSize > 0 {
+		callOptions = append(callOptions, grpc.MaxCallRecvMsgSize(o.MaxRecvMsgSize<<20))
+	}
+	if o.MaxSendMsgSize > 0 {
+		callOptions = append(callOptions, grpc.MaxCallSendMsgSize(o.MaxSendMsgSize<<20))
+	}
+	if o.IsWaitForReady() {
+		callOptions = append(callOptions, grpc.WaitForReady(true))
+	}
+	if len(callOptions) > 0 {
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(callOptions...))
+	}
+
+	return dialOptions
+}
+
+// getCallOptions returns the compressor of the call, the empty compression uses the compression of the server.
+func (c *Client) getCallOptions(compression string) ([]grpc.CallOption, error) {
+	if compression == "" {
+		compression = c.getCallSettings().Compression
+	}
+	if compression == "" || compression == entity.CompressionNone {
+		return nil, nil
+	}
+
+	if encoding.GetCompressor(compression) == nil {
+		return nil, fmt.Errorf("unknown compressor: %s", compression)
+	}
+
+	return []grpc.CallOption{grpc.UseCompressor(compression)}, nil
+}
@@ -2,22 +2,18 @@
 package grpc
 
 import (
+	"container/list"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
-	"errors"
 	"sync"
 	"time"
 
-	"github.com/jhump/protoreflect/dynamic"
-
-	"github.com/forest33/warthog/pkg/logger"
-
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/logger"
 )
 
 const (
@@ -27,53 +23,98 @@ const (
 
 // Client object capable of interacting with Client.
 type Client struct {
-	ctx              context.Context
-	cfg              *entity.Settings
-	log              *logger.Zerolog
-	conn             *grpc.ClientConn
-	queryCtx         context.Context
-	queryCancel      context.CancelFunc
-	queryStartTime   time.Time
-	connectionMux    sync.RWMutex
-	requestCh        chan *dynamic.Message
-	responseCh       chan *entity.QueryResponse
-	closeStreamCh    chan struct{}
-	sentMessages     uint
-	receivedMessaged uint
-	opts             ClientOptions
-	protoPath        []string
-	importPath       []string
+	ctx           context.Context
+	cfg           *entity.Settings
+	log           *logger.Zerolog
+	conn          *grpc.ClientConn
+	web           *webTransport
+	connectionMux sync.RWMutex
+	responseCh    chan *entity.QueryResponse
+	sessions      map[uint64]*session
+	opts          ClientOptions
+	optionsMux    sync.RWMutex
+	cur           *connection
+	pool          *list.List
+	protoPath     []string
+	importPath    []string
 }
 
 // New creates a new Client.
 func New(ctx context.Context, log *logger.Zerolog) *Client {
-	return &Client{
-		ctx:           ctx,
-		log:           log,
-		responseCh:    make(chan *entity.QueryResponse, responseChanCapacity),
-		closeStreamCh: make(chan struct{}, 1),
+	c := &Client{
+		ctx:        ctx,
+		log:        log,
+		responseCh: make(chan *entity.QueryResponse, responseChanCapacity),
+		sessions:   make(map[uint64]*session),
+		pool:       list.New(),
 	}
+
+	go c.closeIdleConnections()
+
+	return c
 }
 
-// SetSettings sets application settings.
+// SetSettings sets application settings, the idle pooled connections dialed with the previous settings are closed.
 func (c *Client) SetSettings(cfg *entity.Settings) {
+	c.optionsMux.Lock()
 	c.cfg = cfg
+	c.optionsMux.Unlock()
+
+	c.removeConnections(func(cn *connection) bool {
+		return cn != c.cur
+	})
+}
+
+// settings returns the application settings.
+func (c *Client) settings() *entity.Settings {
+	c.optionsMux.RLock()
+	defer c.optionsMux.RUnlock()
+	return c.cfg
 }
 
-// Connect connecting to gRPC server.
+// options returns the client options of the last Connect.
+func (c *Client) options() ClientOptions {
+	c.optionsMux.RLock()
+	defer c.optionsMux.RUnlock()
+	return c.opts
+}
+
+// Connect connecting to gRPC server, the pooled connection of the server is used if it has the same connection hash.
 func (c *Client) Connect(addr string, auth *entity.Auth, opts ...ClientOpt) error {
+	var options ClientOptions
 	if defaultOptions != nil {
-		c.opts = *defaultOptions
+		options = *defaultOptions
 	}
 
 	for _, opt := range opts {
 		if opt == nil {
 			continue
 		}
-		opt(&c.opts)
+		opt(&options)
+	}
+
+	c.optionsMux.Lock()
+	c.opts = options
+	c.optionsMux.Unlock()
+
+	if cn := c.takeConnection(options.serverID, options.connectionHash); cn != nil {
+		c.watchConnection(cn)
+		return nil
+	}
+
+	cn := &connection{serverID: options.serverID, hash: options.connectionHash}
+
+	if options.transport != entity.TransportGRPC {
+		web, err := c.newWebTransport(cn, addr, auth)
+		if err != nil {
+			return err
+		}
+		cn.web = web
+		c.addConnection(cn)
+		return nil
 	}
 
-	dialOptions, err := c.getDialOptions()
+	dialOptions, err := c.getDialOptions(cn, addr)
 	if err != nil {
 		return err
 	}
@@ -84,12 +125,39 @@ func (c *Client) Connect(addr string, auth *entity.Auth, opts ...ClientOpt) erro
 		dialOptions = append(dialOptions, opt)
 	}
 
+	if dialer, err := c.getDialer(addr); err != nil {
+		return err
+	} else if dialer = c.getErrorDialer(cn, addr, dialer); dialer != nil {
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialer))
+	}
+
+	if options.authority != "" {
+		dialOptions = append(dialOptions, grpc.WithAuthority(options.authority))
+	}
+	if options.userAgent != "" {
+		dialOptions = append(dialOptions, grpc.WithUserAgent(options.userAgent))
+	}
+	dialOptions = append(dialOptions, c.getCallDialOptions()...)
+	dialOptions = append(dialOptions, c.getConnectParams())
+	dialOptions = append(dialOptions, grpc.WithStatsHandler(attemptsHandler{}))
+	target := addr
+	if _, ok := entity.UnixSocketPath(addr); !ok && options.resolver.IsEnabled() {
+		target = getResolverTarget(addr)
+		dialOptions = append(dialOptions, grpc.WithResolvers(&resolverBuilder{cfg: options.resolver, onError: cn.setError}))
+		if options.serviceConfig == "" {
+			dialOptions = append(dialOptions, grpc.WithDefaultServiceConfig(roundRobinLBConfig))
+		}
+	}
+	if options.serviceConfig != "" {
+		dialOptions = append(dialOptions, grpc.WithDefaultServiceConfig(options.serviceConfig))
+	}
+
 	ctx := c.ctx
-	if !*c.cfg.NonBlockingConnection {
+	if settings := c.settings(); !*settings.NonBlockingConnection {
 		var cancel context.CancelFunc
 		dialOptions = append(dialOptions, grpc.WithBlock())
-		if *c.cfg.ConnectTimeout > 0 {
-			ctx, cancel = context.WithTimeout(c.ctx, time.Second*time.Duration(*c.cfg.ConnectTimeout))
+		if *settings.ConnectTimeout > 0 {
+			ctx, cancel = context.WithTimeout(c.ctx, time.Second*time.Duration(*settings.ConnectTimeout))
 			defer func() {
 				c.connectionMux.Lock()
 				cancel()
@@ -98,65 +166,78 @@ func (c *Client) Connect(addr string, auth *entity.Auth, opts ...ClientOpt) erro
 		}
 	}
 
-	c.conn, err = grpc.DialContext(ctx, addr, dialOptions...)
+	conn, err := grpc.DialContext(ctx, target, dialOptions...)
 	if err != nil {
 		return err
 	}
 
+	cn.conn = conn
+	c.addConnection(cn)
+	c.watchConnection(cn)
+
 	return nil
 }
 
-func (c *Client) getDialOptions() ([]grpc.DialOption, error) {
-	if c.opts.noTLS {
-		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+// watchConnection sends the state transitions of the current connection to the state handler.
+func (c *Client) watchConnection(cn *connection) {
+	if cn.conn == nil {
+		return
 	}
 
-	creds, err := c.loadTLSCredentials()
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.connectionMux.Lock()
+	cn.stateCancel = cancel
+	c.connectionMux.Unlock()
 
-	return []grpc.DialOption{grpc.WithTransportCredentials(creds)}, nil
+	go c.watchState(ctx, cn, c.options().stateHandler)
 }
 
-func (c *Client) loadTLSCredentials() (credentials.TransportCredentials, error) {
-	pool := x509.NewCertPool()
-	if !pool.AppendCertsFromPEM([]byte(c.opts.rootCertificate)) {
-		return nil, errors.New("failed to add server CA's certificate")
+func (c *Client) getDialOptions(cn *connection, addr string) ([]grpc.DialOption, error) {
+	if c.options().noTLS {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
 	}
 
-	// nolint:gosec
-	cfg := &tls.Config{
-		RootCAs:            pool,
-		InsecureSkipVerify: c.opts.insecureSkipVerify,
+	creds, err := c.loadTLSCredentials(cn, addr)
+	if err != nil {
+		return nil, err
 	}
 
-	if c.opts.clientCertificate != "" && c.opts.clientKey != "" {
-		certificates, err := tls.X509KeyPair([]byte(c.opts.clientCertificate), []byte(c.opts.clientKey))
-		if err != nil {
-			return nil, err
-		}
-		cfg.Certificates = []tls.Certificate{certificates}
+	return []grpc.DialOption{grpc.WithTransportCredentials(&errorCredentials{TransportCredentials: creds, cn: cn})}, nil
+}
+
+func (c *Client) loadTLSCredentials(cn *connection, addr string) (credentials.TransportCredentials, error) {
+	cfg, err := c.getTLSConfig(cn, addr)
+	if err != nil {
+		return nil, err
 	}
 
 	return credentials.NewTLS(cfg), nil
 }
 
-// Close closes connection to gRPC server.
+// Close releases the connection to gRPC server, the pooled connection is kept open for the next Connect,
+// the connection used by the running sessions is closed when the last of them is finished.
 func (c *Client) Close() {
 	c.connectionMux.Lock()
 	defer c.connectionMux.Unlock()
 
-	if c.conn != nil {
-		if err := c.conn.Close(); err != nil {
-			c.log.Error().Msgf("failed to close connection: %v", err)
-		}
+	cn := c.cur
+	if cn == nil {
+		return
 	}
+
+	if cn.stateCancel != nil {
+		cn.stateCancel()
+		cn.stateCancel = nil
+	}
+	cn.lastUsed = time.Now()
+
+	c.cur, c.conn, c.web = nil, nil, nil
+	c.closeUnused(cn.conn, cn.web)
 }
 
 func (c *Client) isConnected() bool {
 	c.connectionMux.RLock()
 	defer c.connectionMux.RUnlock()
 
-	return c.conn != nil
+	return (c.conn != nil && c.conn.GetState() != connectivity.Shutdown) || c.web != nil
 }
@@ -1,24 +1,70 @@
 // Package grpc provides basic gRPC functions.
 package grpc
 
+import "github.com/forest33/warthog/business/entity"
+
 // ClientOptions represents Client options.
 type ClientOptions struct {
-	noTLS              bool
-	insecureSkipVerify bool
-	rootCertificate    string
-	clientCertificate  string
-	clientKey          string
+	noTLS                 bool
+	insecureSkipVerify    bool
+	rootCertificate       string
+	clientCertificate     string
+	clientKey             string
+	systemRoots           bool
+	rootCertificatePath   string
+	clientCertificatePath string
+	clientKeyPath         string
+	pkcs12Path            string
+	pkcs12Password        string
+	minTLSVersion         string
+	maxTLSVersion         string
+	cipherSuites          []string
+	alpn                  []string
+	transport             string
+	http2                 bool
+	proxy                 *entity.Proxy
+	authority             string
+	serverName            string
+	userAgent             string
+	callOptions           *entity.CallOptions
+	serviceConfig         string
+	resolver              *entity.Resolver
+	stateHandler          StateHandler
+	serverID              int64
+	connectionHash        string
 }
 
 // ClientOpt represents Client option.
 type ClientOpt func(options *ClientOptions)
 
 var defaultOptions = &ClientOptions{
-	noTLS:              false,
-	insecureSkipVerify: false,
-	rootCertificate:    "",
-	clientCertificate:  "",
-	clientKey:          "",
+	noTLS:                 false,
+	insecureSkipVerify:    false,
+	rootCertificate:       "",
+	clientCertificate:     "",
+	clientKey:             "",
+	systemRoots:           false,
+	rootCertificatePath:   "",
+	clientCertificatePath: "",
+	clientKeyPath:         "",
+	pkcs12Path:            "",
+	pkcs12Password:        "",
+	minTLSVersion:         "",
+	maxTLSVersion:         "",
+	cipherSuites:          nil,
+	alpn:                  nil,
+	transport:             entity.TransportGRPC,
+	http2:                 false,
+	proxy:                 nil,
+	authority:             "",
+	serverName:            "",
+	userAgent:             "",
+	callOptions:           nil,
+	serviceConfig:         "",
+	resolver:              nil,
+	stateHandler:          nil,
+	serverID:              0,
+	connectionHash:        "",
 }
 
 // WithNoTLS returns ClientOpt which disables transport security.
@@ -55,3 +101,135 @@ func WithClientKey(key string) ClientOpt {
 		options.clientKey = key
 	}
 }
+
+// WithSystemRoots returns ClientOpt which adds the system CA pool to the trusted root certificates.
+func WithSystemRoots() ClientOpt {
+	return func(options *ClientOptions) {
+		options.systemRoots = true
+	}
+}
+
+// WithRootCertificatePath returns ClientOpt which sets the file of the server CA certificate.
+func WithRootCertificatePath(path string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.rootCertificatePath = path
+	}
+}
+
+// WithClientCertificatePath returns ClientOpt which sets the files of the client certificate and private key.
+func WithClientCertificatePath(certPath, keyPath string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.clientCertificatePath = certPath
+		options.clientKeyPath = keyPath
+	}
+}
+
+// WithPKCS12 returns ClientOpt which sets the PKCS#12 bundle of the client certificate and private key.
+func WithPKCS12(path, password string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.pkcs12Path = path
+		options.pkcs12Password = password
+	}
+}
+
+// WithTLSVersions returns ClientOpt which sets the minimum and maximum TLS versions, empty uses the default.
+func WithTLSVersions(minVersion, maxVersion string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.minTLSVersion = minVersion
+		options.maxTLSVersion = maxVersion
+	}
+}
+
+// WithCipherSuites returns ClientOpt which sets the enabled TLS 1.0-1.2 cipher suites.
+func WithCipherSuites(names []string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.cipherSuites = names
+	}
+}
+
+// WithALPN returns ClientOpt which sets the application protocols offered in the TLS handshake.
+func WithALPN(protocols []string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.alpn = protocols
+	}
+}
+
+// WithTransport returns ClientOpt which sets the protocol used to call methods.
+func WithTransport(transport string) ClientOpt {
+	return func(options *ClientOptions) {
+		if transport != "" {
+			options.transport = transport
+		}
+	}
+}
+
+// WithHTTP2 returns ClientOpt which enables HTTP/2 for the HTTP based transports.
+func WithHTTP2() ClientOpt {
+	return func(options *ClientOptions) {
+		options.http2 = true
+	}
+}
+
+// WithProxy returns ClientOpt which sets the proxy of the server, nil uses the proxy from the settings.
+func WithProxy(proxy *entity.Proxy) ClientOpt {
+	return func(options *ClientOptions) {
+		options.proxy = proxy
+	}
+}
+
+// WithAuthority returns ClientOpt which overrides the :authority pseudo-header (the Host header for the HTTP based transports).
+func WithAuthority(authority string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.authority = authority
+	}
+}
+
+// WithServerName returns ClientOpt which overrides the TLS server name (SNI) used to verify the server certificate.
+func WithServerName(name string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.serverName = name
+	}
+}
+
+// WithUserAgent returns ClientOpt which sets the user agent.
+func WithUserAgent(userAgent string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.userAgent = userAgent
+	}
+}
+
+// WithCallOptions returns ClientOpt which sets the compression, message size limits, keepalive and wait for ready options.
+func WithCallOptions(opts *entity.CallOptions) ClientOpt {
+	return func(options *ClientOptions) {
+		options.callOptions = opts
+	}
+}
+
+// WithServiceConfig returns ClientOpt which sets the default service config JSON with the retry, hedging and load balancing policies.
+func WithServiceConfig(cfg string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.serviceConfig = cfg
+	}
+}
+
+// WithResolver returns ClientOpt which sets the resolver of the server backends and the host overrides.
+func WithResolver(r *entity.Resolver) ClientOpt {
+	return func(options *ClientOptions) {
+		options.resolver = r
+	}
+}
+
+// WithStateHandler returns ClientOpt which sets the handler of the connectivity state transitions.
+func WithStateHandler(h StateHandler) ClientOpt {
+	return func(options *ClientOptions) {
+		options.stateHandler = h
+	}
+}
+
+// WithPoolKey returns ClientOpt which keeps the connection in the pool by the server ID and the hash of its connection settings.
+func WithPoolKey(serverID int64, connectionHash string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.serverID = serverID
+		options.connectionHash = connectionHash
+	}
+}
@@ -0,0 +1,205 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	connPoolSize            = 8
+	connPoolIdleTimeout     = 10 * time.Minute
+	connPoolCleanupInterval = time.Minute
+)
+
+// connection dialed connection to the server with the results of its handshakes and connection attempts.
+type connection struct {
+	serverID    int64
+	hash        string
+	conn        *grpc.ClientConn
+	web         *webTransport
+	lastUsed    time.Time
+	stateCancel context.CancelFunc
+	tlsInfo     *entity.TLSInfo
+	tlsMux      sync.RWMutex
+	err         error
+	errMux      sync.RWMutex
+}
+
+// isPooled checks whether the connection is kept in the pool after it is released.
+func (cn *connection) isPooled() bool {
+	return cn.hash != ""
+}
+
+// isAlive checks whether the connection can be used again.
+func (cn *connection) isAlive() bool {
+	return cn.web != nil || (cn.conn != nil && cn.conn.GetState() != connectivity.Shutdown)
+}
+
+func (cn *connection) setTLSInfo(info *entity.TLSInfo) {
+	cn.tlsMux.Lock()
+	defer cn.tlsMux.Unlock()
+	cn.tlsInfo = info
+}
+
+func (cn *connection) getTLSInfo() *entity.TLSInfo {
+	cn.tlsMux.RLock()
+	defer cn.tlsMux.RUnlock()
+	return cn.tlsInfo
+}
+
+func (cn *connection) setError(err error) {
+	cn.errMux.Lock()
+	defer cn.errMux.Unlock()
+	cn.err = err
+}
+
+func (cn *connection) getError() error {
+	cn.errMux.RLock()
+	defer cn.errMux.RUnlock()
+	return cn.err
+}
+
+// takeConnection makes the live pooled connection of the server current, nil if there is no such connection.
+func (c *Client) takeConnection(serverID int64, hash string) *connection {
+	if hash == "" {
+		return nil
+	}
+
+	c.connectionMux.Lock()
+	defer c.connectionMux.Unlock()
+
+	for e := c.pool.Front(); e != nil; e = e.Next() {
+		cn := e.Value.(*connection)
+		if cn.serverID != serverID || cn.hash != hash {
+			continue
+		}
+		if !cn.isAlive() {
+			c.pool.Remove(e)
+			c.closeUnused(cn.conn, cn.web)
+			return nil
+		}
+		c.pool.MoveToFront(e)
+		cn.lastUsed = time.Now()
+		c.setCurrent(cn)
+		return cn
+	}
+
+	return nil
+}
+
+// addConnection makes the new connection current and puts it into the pool,
+// the least recently used connection is closed if the pool is full.
+func (c *Client) addConnection(cn *connection) {
+	c.connectionMux.Lock()
+	defer c.connectionMux.Unlock()
+
+	cn.lastUsed = time.Now()
+	c.setCurrent(cn)
+
+	if !cn.isPooled() {
+		return
+	}
+
+	c.pool.PushFront(cn)
+	for c.pool.Len() > connPoolSize {
+		e := c.pool.Back()
+		if e.Value.(*connection) == c.cur {
+			break
+		}
+		c.pool.Remove(e)
+		c.closeUnused(e.Value.(*connection).conn, e.Value.(*connection).web)
+	}
+}
+
+func (c *Client) setCurrent(cn *connection) {
+	c.cur = cn
+	c.conn = cn.conn
+	c.web = cn.web
+}
+
+// InvalidateConnections closes the pooled connections of the server, the current connection is closed when it is released.
+func (c *Client) InvalidateConnections(serverID int64) {
+	c.removeConnections(func(cn *connection) bool {
+		return cn.serverID == serverID
+	})
+}
+
+// removeConnections removes the pooled connections matched by the filter and closes them if they are not used.
+func (c *Client) removeConnections(filter func(cn *connection) bool) {
+	c.connectionMux.Lock()
+	defer c.connectionMux.Unlock()
+
+	var next *list.Element
+	for e := c.pool.Front(); e != nil; e = next {
+		next = e.Next()
+		cn := e.Value.(*connection)
+		if !filter(cn) {
+			continue
+		}
+		c.pool.Remove(e)
+		c.closeUnused(cn.conn, cn.web)
+	}
+}
+
+// closeIdleConnections closes the pooled connections that have not been used during the idle timeout.
+func (c *Client) closeIdleConnections() {
+	ticker := time.NewTicker(connPoolCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.removeConnections(func(cn *connection) bool {
+				return cn != c.cur && time.Since(cn.lastUsed) > connPoolIdleTimeout
+			})
+		}
+	}
+}
+
+// closeUnused closes the connection unless it is current, kept in the pool or used by the running sessions.
+func (c *Client) closeUnused(conn *grpc.ClientConn, web *webTransport) {
+	if c.isUsedConn(conn, web) {
+		return
+	}
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			c.log.Error().Msgf("failed to close connection: %v", err)
+		}
+	}
+	if web != nil {
+		web.close()
+	}
+}
+
+// isUsedConn checks whether the connection is current, kept in the pool or used by the running sessions.
+func (c *Client) isUsedConn(conn *grpc.ClientConn, web *webTransport) bool {
+	isSame := func(cn *grpc.ClientConn, w *webTransport) bool {
+		return (conn != nil && cn == conn) || (web != nil && w == web)
+	}
+
+	if isSame(c.conn, c.web) {
+		return true
+	}
+	for e := c.pool.Front(); e != nil; e = e.Next() {
+		if cn := e.Value.(*connection); isSame(cn.conn, cn.web) {
+			return true
+		}
+	}
+	for _, s := range c.sessions {
+		if isSame(s.conn, s.web) {
+			return true
+		}
+	}
+
+	return false
+}
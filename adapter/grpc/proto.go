@@ -92,7 +92,11 @@ func (c *Client) LoadFromProtobuf() ([]*entity.Service, []*entity.ProtobufError,
 
 // LoadFromReflection loads services using reflection.
 func (c *Client) LoadFromReflection() ([]*entity.Service, error) {
-	ctx, cancel := context.WithTimeout(c.ctx, time.Second*time.Duration(*c.cfg.ConnectTimeout))
+	if c.web != nil {
+		return nil, fmt.Errorf("server reflection is not supported by the %s transport", c.options().transport)
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, time.Second*time.Duration(*c.settings().ConnectTimeout))
 	defer cancel()
 
 	client := grpcreflect.NewClientAuto(ctx, c.conn)
@@ -131,10 +135,11 @@ func (c *Client) getMethods(sd *desc.ServiceDescriptor) []*entity.Method {
 			Type:       getMethodType(md),
 			Descriptor: md,
 			Input:      c.getFields(md.GetInputType().GetFields(), nil, nil),
+			HTTPRule:   getHTTPRule(md),
 		})
 	}
 
-	if *c.cfg.SortMethodsByName {
+	if *c.settings().SortMethodsByName {
 		c.sortMethodsByName(methods)
 	}
 
@@ -308,7 +313,7 @@ func (c *Client) getMessageFields(fields []*desc.FieldDescriptor, fqn map[string
 
 	for _, mf := range fields {
 		name := mf.GetFullyQualifiedName()
-		if count, ok := fqn[name]; ok && count >= *c.cfg.MaxLoopDepth {
+		if count, ok := fqn[name]; ok && count >= *c.settings().MaxLoopDepth {
 			continue
 		}
 		fqn[name]++
@@ -0,0 +1,142 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+type contextDialer func(ctx context.Context, addr string) (net.Conn, error)
+
+// bufferedConn returns the data read ahead from the proxy connection before reading the connection itself.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// getProxy returns the server proxy or the proxy from the settings.
+func (c *Client) getProxy() *entity.Proxy {
+	if proxy := c.options().proxy; !proxy.IsDefault() {
+		return proxy
+	}
+	if settings := c.settings(); settings != nil {
+		return settings.Proxy
+	}
+	return nil
+}
+
+// getDialer returns the dialer connecting through the proxy, nil if the proxy is disabled.
+// Unix socket addresses are always dialed directly.
+func (c *Client) getDialer(target string) (contextDialer, error) {
+	p := c.getProxy()
+	if !p.IsEnabled() {
+		return nil, nil
+	}
+	if _, ok := entity.UnixSocketPath(target); ok {
+		return nil, nil
+	}
+
+	addr := strings.TrimPrefix(strings.TrimPrefix(p.Addr, "http://"), "socks5://")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("wrong proxy address %s: %v", p.Addr, err)
+	}
+
+	var dial contextDialer
+	switch p.Type {
+	case entity.ProxyTypeHTTP:
+		dial = func(ctx context.Context, target string) (net.Conn, error) {
+			return dialHTTPProxy(ctx, addr, target, p.Login, p.Password)
+		}
+	case entity.ProxyTypeSOCKS5:
+		var auth *proxy.Auth
+		if p.Login != "" || p.Password != "" {
+			auth = &proxy.Auth{User: p.Login, Password: p.Password}
+		}
+		d, err := proxy.SOCKS5("tcp", addr, auth, &net.Dialer{})
+		if err != nil {
+			return nil, err
+		}
+		cd, ok := d.(proxy.ContextDialer)
+		if !ok {
+			return nil, errors.New("SOCKS5 dialer does not support context")
+		}
+		dial = func(ctx context.Context, target string) (net.Conn, error) {
+			return cd.DialContext(ctx, "tcp", target)
+		}
+	default:
+		return nil, fmt.Errorf("unknown proxy type: %s", p.Type)
+	}
+
+	return func(ctx context.Context, target string) (net.Conn, error) {
+		conn, err := dial(ctx, target)
+		if err != nil {
+			c.log.Error().Msgf("failed to connect to %s through the %s proxy %s: %v", target, p.Type, addr, err)
+		}
+		return conn, err
+	}, nil
+}
+
+// dialHTTPProxy establishes the tunnel to the target by the HTTP CONNECT method.
+func dialHTTPProxy(ctx context.Context, proxyAddr, target, login, password string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: http.Header{},
+	}
+	if login != "" || password != "" {
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(login+":"+password)))
+	}
+
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT %s: %s", target, resp.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	if r.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: r}, nil
+	}
+
+	return conn, nil
+}
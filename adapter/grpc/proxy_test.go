@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/logger"
+)
+
+// connectProxy HTTP CONNECT proxy recording the requested targets.
+type connectProxy struct {
+	targets []string
+	mux     sync.Mutex
+}
+
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" {
+		http.Error(w, "wrong credentials", http.StatusProxyAuthRequired)
+		return
+	}
+
+	upstream, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	p.mux.Lock()
+	p.targets = append(p.targets, r.Host)
+	p.mux.Unlock()
+
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		return
+	}
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	go func() {
+		_, _ = io.Copy(upstream, buf)
+		_ = upstream.Close()
+	}()
+	_, _ = io.Copy(conn, upstream)
+	_ = conn.Close()
+}
+
+func (p *connectProxy) getTargets() []string {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return append([]string(nil), p.targets...)
+}
+
+func TestConnectThroughHTTPProxy(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	proxy := &connectProxy{}
+	proxySrv := httptest.NewServer(proxy)
+	defer proxySrv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	settings := *entity.DefaultSettings
+	c := New(ctx, logger.NewDefaultZerolog())
+	c.SetSettings(&settings)
+	defer c.Close()
+
+	addr := lis.Addr().String()
+	err = c.Connect(addr, nil, WithNoTLS(), WithProxy(&entity.Proxy{
+		Type:     entity.ProxyTypeHTTP,
+		Addr:     strings.TrimPrefix(proxySrv.URL, "http://"),
+		Login:    "user",
+		Password: "pass",
+	}))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("failed to call the server through the proxy: %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("unexpected health status: %s", resp.GetStatus())
+	}
+
+	targets := proxy.getTargets()
+	if len(targets) == 0 {
+		t.Fatal("the connection did not go through the proxy")
+	}
+	for _, target := range targets {
+		if target != addr {
+			t.Errorf("proxy target %s, expected %s", target, addr)
+		}
+	}
+}
+
+func TestConnectThroughHTTPProxyWrongCredentials(t *testing.T) {
+	proxySrv := httptest.NewServer(&connectProxy{})
+	defer proxySrv.Close()
+
+	p := &entity.Proxy{Type: entity.ProxyTypeHTTP, Addr: strings.TrimPrefix(proxySrv.URL, "http://"), Login: "user"}
+	c := New(context.Background(), logger.NewDefaultZerolog())
+	c.opts.proxy = p
+
+	dial, err := c.getDialer("127.0.0.1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := dial(context.Background(), "127.0.0.1:1")
+	if err == nil {
+		_ = conn.Close()
+		t.Fatal("expected the proxy to reject the credentials")
+	}
+	if !strings.Contains(err.Error(), "407") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
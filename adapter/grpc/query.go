@@ -3,6 +3,8 @@ package grpc
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/dynamic"
 	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
 	"google.golang.org/grpc"
@@ -23,7 +26,7 @@ import (
 	"github.com/forest33/warthog/business/entity"
 )
 
-func (c *Client) createMessage(method *entity.Method, data map[string]interface{}, metadata []string) (ms *dynamic.Message, err error) {
+func (c *Client) newMessage(method *entity.Method, data map[string]interface{}) (ms *dynamic.Message, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -59,64 +62,177 @@ func (c *Client) createMessage(method *entity.Method, data map[string]interface{
 		}
 	}
 
-	if *c.cfg.RequestTimeout > 0 && method.Type == entity.MethodTypeUnary {
-		c.queryCtx, c.queryCancel = context.WithTimeout(c.ctx, time.Second*time.Duration(*c.cfg.RequestTimeout))
-	} else {
-		c.queryCtx, c.queryCancel = context.WithCancel(c.ctx)
+	return
+}
+
+// MessageToJSON returns the request data as the protobuf JSON.
+func (c *Client) MessageToJSON(method *entity.Method, data map[string]interface{}) (string, error) {
+	ms, err := c.newMessage(method, data)
+	if err != nil {
+		return "", err
 	}
 
-	c.queryCtx = addMetadata(c.queryCtx, metadata)
+	buf, err := ms.MarshalJSONPB(&jsonpb.Marshaler{OrigName: true})
+	if err != nil {
+		return "", err
+	}
 
-	return
+	return string(buf), nil
 }
 
-// Query executes a gRPC request.
-func (c *Client) Query(method *entity.Method, data map[string]interface{}, metadata []string) error {
+// JSONToMessage parses the protobuf JSON of the method input, the result is keyed by the original
+// field names, the well-known types are decoded as regular messages and enums as numbers.
+func (c *Client) JSONToMessage(method *entity.Method, body string) (map[string]interface{}, error) {
+	ms := dynamic.NewMessage(method.Descriptor.GetInputType())
+	if err := ms.UnmarshalJSONPB(&jsonpb.Unmarshaler{}, []byte(body)); err != nil {
+		return nil, err
+	}
+
+	return messageToMap(ms)
+}
+
+func messageToMap(ms *dynamic.Message) (map[string]interface{}, error) {
+	data := make(map[string]interface{}, len(ms.GetKnownFields()))
+
+	for _, fd := range ms.GetMessageDescriptor().GetFields() {
+		if !ms.HasField(fd) {
+			continue
+		}
+
+		v, err := fieldToValue(fd, ms.GetField(fd))
+		if err != nil {
+			return nil, err
+		}
+		data[fd.GetName()] = v
+	}
+
+	return data, nil
+}
+
+func fieldToValue(fd *desc.FieldDescriptor, v interface{}) (interface{}, error) {
+	switch {
+	case fd.IsMap():
+		items, _ := v.(map[interface{}]interface{})
+		obj := make(map[string]interface{}, len(items))
+		for k, item := range items {
+			value, err := fieldToValue(fd.GetMapValueType(), item)
+			if err != nil {
+				return nil, err
+			}
+			obj[fmt.Sprint(k)] = value
+		}
+		return obj, nil
+	case fd.IsRepeated():
+		items, _ := v.([]interface{})
+		list := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			value, err := scalarToValue(fd, item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	default:
+		return scalarToValue(fd, v)
+	}
+}
+
+func scalarToValue(fd *desc.FieldDescriptor, v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t), nil
+	case bool, string:
+		return t, nil
+	case proto.Message:
+		ms, err := dynamic.AsDynamicMessage(t)
+		if err != nil {
+			return nil, err
+		}
+		return messageToMap(ms)
+	default:
+		return fmt.Sprint(t), nil
+	}
+}
+
+// Query executes a gRPC request in the session, the empty compression uses the compression of the server.
+// The message is sent to the running client or bidirectional stream of the session if there is one,
+// otherwise the new session is started on the current connection.
+func (c *Client) Query(sessionID uint64, method *entity.Method, data map[string]interface{}, metadata []string, compression string) error {
+	if s := c.getSession(sessionID); s != nil && s.isClientStream() {
+		ms, err := c.newMessage(method, data)
+		if err != nil {
+			c.responseError(s, err, "")
+			return err
+		}
+		s.request(ms)
+		return nil
+	}
+
 	if !c.isConnected() {
 		return entity.ErrNotConnected
 	}
 
-	ms, err := c.createMessage(method, data, metadata)
+	s := c.newSession(sessionID, method, metadata)
+
+	callOpts, err := c.getCallOptions(compression)
+	if err != nil {
+		c.responseError(s, err, "")
+		c.endSession(s)
+		return err
+	}
+
+	ms, err := c.newMessage(method, data)
 	if err != nil {
-		c.responseError(err, "")
+		c.responseError(s, err, "")
+		c.endSession(s)
 		return err
 	}
 
-	var isNew bool
+	if s.web != nil {
+		return c.webQuery(s, ms)
+	}
 
 	switch method.Type {
 	case entity.MethodTypeUnary:
-		c.unary(method, ms)
+		go c.unary(s, ms, callOpts)
 	case entity.MethodTypeClientStream:
-		isNew, err = c.clientStream(method)
+		err = c.clientStream(s, callOpts)
 	case entity.MethodTypeServerStream:
-		c.serverStream(method, ms)
+		c.serverStream(s, ms, callOpts)
 	case entity.MethodTypeBidiStream:
-		c.bidiStream(method)
+		c.bidiStream(s, callOpts)
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if isNew || method.Type == entity.MethodTypeClientStream || method.Type == entity.MethodTypeBidiStream {
-		c.request(ms)
+	if s.isClientStream() {
+		s.request(ms)
 	}
 
 	return nil
 }
 
-// CancelQuery aborting a running gRPC request.
-func (c *Client) CancelQuery() {
-	if c.queryCancel == nil {
-		return
+// CancelQuery aborting a running gRPC request of the session, all sessions are aborted if the ID is zero.
+func (c *Client) CancelQuery(sessionID uint64) {
+	for _, s := range c.getSessions(sessionID) {
+		s.cancel()
 	}
-	c.queryCancel()
 }
 
-// CloseStream stops a running gRPC stream.
-func (c *Client) CloseStream() {
-	c.closeStreamCh <- struct{}{}
+// CloseStream stops a running gRPC stream of the session, all streams are stopped if the ID is zero.
+func (c *Client) CloseStream(sessionID uint64) {
+	for _, s := range c.getSessions(sessionID) {
+		if !s.isClientStream() {
+			continue
+		}
+		select {
+		case s.closeCh <- struct{}{}:
+		default:
+		}
+	}
 }
 
 // GetResponseChannel returns response channel.
@@ -124,186 +240,181 @@ func (c *Client) GetResponseChannel() chan *entity.QueryResponse {
 	return c.responseCh
 }
 
-// GetSentCounter returns sent messages counter.
-func (c *Client) GetSentCounter() uint {
-	return c.sentMessages
+// GetSentCounter returns sent messages counter of the session.
+func (c *Client) GetSentCounter(sessionID uint64) uint {
+	if s := c.getSession(sessionID); s != nil {
+		return uint(s.sent.Load())
+	}
+	return 0
 }
 
-func (c *Client) unary(method *entity.Method, ms *dynamic.Message) {
+func (c *Client) unary(s *session, ms *dynamic.Message, callOpts []grpc.CallOption) {
 	var (
 		header  metadata.MD
 		trailer metadata.MD
 	)
 
-	stub := grpcdynamic.NewStub(c.conn)
-	c.queryStartTime = time.Now()
-	resp, err := stub.InvokeRpc(c.queryCtx, method.Descriptor, ms, grpc.Header(&header), grpc.Trailer(&trailer))
-	c.response(resp, header, trailer, err)
+	defer c.endSession(s)
 
-	c.sentMessages = 0
-	c.receivedMessaged = 0
+	stub := grpcdynamic.NewStub(s.conn)
+	resp, err := stub.InvokeRpc(s.ctx, s.method.Descriptor, ms, append(callOpts, grpc.Header(&header), grpc.Trailer(&trailer))...)
+	c.response(s, resp, header, trailer, err)
 }
 
-func (c *Client) clientStream(method *entity.Method) (bool, error) {
+func (c *Client) clientStream(s *session, callOpts []grpc.CallOption) error {
 	var (
 		header  metadata.MD
 		trailer metadata.MD
-		stream  *grpcdynamic.ClientStream
-		err     error
 	)
 
-	isNew := c.startRequest()
-	if isNew {
-		stub := grpcdynamic.NewStub(c.conn)
-		stream, err = stub.InvokeRpcClientStream(c.queryCtx, method.Descriptor, grpc.Header(&header), grpc.Trailer(&trailer))
-		if err != nil {
-			c.responseError(err, "")
-			return isNew, err
-		}
-
-		go func() {
-			defer func() {
-				c.stopRequest()
-			}()
-
-			c.queryStartTime = time.Now()
+	stub := grpcdynamic.NewStub(s.conn)
+	stream, err := stub.InvokeRpcClientStream(s.ctx, s.method.Descriptor, append(callOpts, grpc.Header(&header), grpc.Trailer(&trailer))...)
+	if err != nil {
+		c.responseError(s, err, "")
+		c.endSession(s)
+		return err
+	}
 
-			for {
-				select {
-				case <-c.queryCtx.Done():
-					c.response(nil, header, trailer, status.FromContextError(context.Canceled).Err())
-					_, _ = stream.CloseAndReceive()
-					c.log.Debug().Msg("stream canceled")
+	go func() {
+		defer c.endSession(s)
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				c.response(s, nil, header, trailer, status.FromContextError(context.Canceled).Err())
+				_, _ = stream.CloseAndReceive()
+				c.log.Debug().Msgf("stream of session %d canceled", s.id)
+				return
+			case <-s.closeCh:
+				if err := s.sendPending(stream); err != nil {
+					c.response(s, nil, header, trailer, err)
 					return
-				case <-c.closeStreamCh:
-					data, err := stream.CloseAndReceive()
-					c.response(data, header, trailer, err)
-					c.log.Debug().Msg("close & receive stream")
+				}
+				data, err := stream.CloseAndReceive()
+				c.response(s, data, header, trailer, err)
+				c.log.Debug().Msgf("close & receive stream of session %d", s.id)
+				return
+			case ms := <-s.requestCh:
+				if err := stream.SendMsg(ms); err != nil {
+					c.response(s, nil, header, trailer, err)
 					return
-				case ms := <-c.requestCh:
-					if err := stream.SendMsg(ms); err != nil {
-						c.response(nil, header, trailer, err)
-						return
-					}
 				}
 			}
-		}()
-	}
+		}
+	}()
 
-	return isNew, nil
+	return nil
 }
 
-func (c *Client) serverStream(method *entity.Method, ms *dynamic.Message) {
+func (c *Client) serverStream(s *session, ms *dynamic.Message, callOpts []grpc.CallOption) {
 	var (
 		header  metadata.MD
 		trailer metadata.MD
 		isBreak bool
 	)
 
-	if c.startRequest() {
-		stub := grpcdynamic.NewStub(c.conn)
-		c.queryStartTime = time.Now()
-		stream, err := stub.InvokeRpcServerStream(c.queryCtx, method.Descriptor, ms, grpc.Header(&header), grpc.Trailer(&trailer))
-		if err != nil {
-			c.responseError(err, "")
-			return
-		}
+	stub := grpcdynamic.NewStub(s.conn)
+	stream, err := stub.InvokeRpcServerStream(s.ctx, s.method.Descriptor, ms, append(callOpts, grpc.Header(&header), grpc.Trailer(&trailer))...)
+	if err != nil {
+		c.responseError(s, err, "")
+		c.endSession(s)
+		return
+	}
 
-		go func() {
-			defer func() {
-				c.stopRequest()
-			}()
-
-			for !isBreak {
-				data, err := stream.RecvMsg()
-				if errors.Is(err, io.EOF) {
-					isBreak = true
-				} else if status.Code(err) == codes.Canceled {
-					c.responseError(err, time.Since(c.queryStartTime).String())
-					return
-				} else if err != nil {
-					c.responseError(err, "")
-					c.log.Error().Msgf("failed to receive message: %v", err)
-					return
-				}
-				header, hErr := stream.Header()
-				if hErr != nil {
-					c.log.Error().Msgf("failed to get message header: %v", err)
-				}
-				trailer = stream.Trailer()
-				c.response(data, header, trailer, nil)
+	go func() {
+		defer c.endSession(s)
+
+		for !isBreak {
+			data, err := stream.RecvMsg()
+			if errors.Is(err, io.EOF) {
+				isBreak = true
+			} else if status.Code(err) == codes.Canceled {
+				c.responseError(s, err, time.Since(s.startTime).String())
+				return
+			} else if err != nil {
+				c.responseError(s, err, "")
+				c.log.Error().Msgf("failed to receive message: %v", err)
+				return
 			}
-		}()
-	}
+			header, hErr := stream.Header()
+			if hErr != nil {
+				c.log.Error().Msgf("failed to get message header: %v", err)
+			}
+			trailer = stream.Trailer()
+			c.response(s, data, header, trailer, nil)
+		}
+	}()
 }
 
-func (c *Client) bidiStream(method *entity.Method) {
+func (c *Client) bidiStream(s *session, callOpts []grpc.CallOption) {
 	var (
 		header  metadata.MD
 		trailer metadata.MD
 		isBreak bool
 	)
 
-	if c.startRequest() {
-		stub := grpcdynamic.NewStub(c.conn)
-		c.queryStartTime = time.Now()
-		stream, err := stub.InvokeRpcBidiStream(c.queryCtx, method.Descriptor, grpc.Header(&header), grpc.Trailer(&trailer))
-		if err != nil {
-			c.responseError(err, "")
-			return
+	stub := grpcdynamic.NewStub(s.conn)
+	stream, err := stub.InvokeRpcBidiStream(s.ctx, s.method.Descriptor, append(callOpts, grpc.Header(&header), grpc.Trailer(&trailer))...)
+	if err != nil {
+		c.responseError(s, err, "")
+		c.endSession(s)
+		return
+	}
+
+	go func() {
+		defer c.endSession(s)
+
+		for !isBreak {
+			data, err := stream.RecvMsg()
+			if errors.Is(err, io.EOF) || isStreamEOF(err) {
+				isBreak = true
+			} else if status.Code(err) == codes.Canceled {
+				c.responseError(s, err, time.Since(s.startTime).String())
+				return
+			} else if err != nil {
+				c.responseError(s, err, time.Since(s.startTime).String())
+				c.log.Error().Msgf("failed to receive message: %v", err)
+				return
+			}
+			header, hErr := stream.Header()
+			if hErr != nil {
+				c.log.Error().Msgf("failed to get message header: %v", err)
+			}
+			trailer = stream.Trailer()
+			c.response(s, data, header, trailer, err)
 		}
+	}()
 
-		go func() {
-			defer func() {
-				c.stopRequest()
-			}()
-
-			for !isBreak {
-				data, err := stream.RecvMsg()
-				if errors.Is(err, io.EOF) || isStreamEOF(err) {
-					isBreak = true
-				} else if status.Code(err) == codes.Canceled {
-					c.responseError(err, time.Since(c.queryStartTime).String())
-					return
-				} else if err != nil {
-					c.responseError(err, time.Since(c.queryStartTime).String())
-					c.log.Error().Msgf("failed to receive message: %v", err)
+	// the cancellation is reported by the receiver
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.ctx.Done():
+				_ = stream.CloseSend()
+				return
+			case <-s.closeCh:
+				if err := s.sendPending(stream); err != nil {
+					c.response(s, nil, header, trailer, err)
 					return
 				}
-				header, hErr := stream.Header()
-				if hErr != nil {
-					c.log.Error().Msgf("failed to get message header: %v", err)
+				if err := stream.CloseSend(); err != nil {
+					c.log.Error().Msgf("failed to close stream: %v", err)
 				}
-				trailer = stream.Trailer()
-				c.response(data, header, trailer, err)
-			}
-		}()
-
-		go func() {
-			for {
-				select {
-				case <-c.queryCtx.Done():
-					c.response(nil, header, trailer, status.FromContextError(context.Canceled).Err())
-					_ = stream.CloseSend()
-					return
-				case <-c.closeStreamCh:
-					if err := stream.CloseSend(); err != nil {
-						c.log.Error().Msgf("failed to close stream: %v", err)
-					}
-					c.log.Debug().Msg("close & send stream")
+				c.log.Debug().Msgf("close & send stream of session %d", s.id)
+				return
+			case ms := <-s.requestCh:
+				if ms == nil {
+					continue
+				}
+				if err := stream.SendMsg(ms); err != nil {
+					c.response(s, nil, header, trailer, err)
 					return
-				case ms := <-c.requestCh:
-					if ms == nil {
-						continue
-					}
-					if err := stream.SendMsg(ms); err != nil {
-						c.response(nil, header, trailer, err)
-						return
-					}
 				}
 			}
-		}()
-	}
+		}
+	}()
 }
 
 func (c *Client) getResponse(m proto.Message) (string, error) {
@@ -313,7 +424,7 @@ func (c *Client) getResponse(m proto.Message) (string, error) {
 
 	switch t := m.(type) {
 	case *dynamic.Message:
-		buf, err := t.MarshalJSONPB(&jsonpb.Marshaler{Indent: "  ", OrigName: true, EmitDefaults: c.cfg.IsEmitDefaults()})
+		buf, err := t.MarshalJSONPB(&jsonpb.Marshaler{Indent: "  ", OrigName: true, EmitDefaults: c.settings().IsEmitDefaults()})
 		if err != nil {
 			return err.Error(), err
 		}
@@ -329,6 +440,34 @@ func (c *Client) getResponse(m proto.Message) (string, error) {
 	}
 }
 
+// getMessage decodes the response message with default values, it is used to extract values from the response.
+func (c *Client) getMessage(m proto.Message) (interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	switch t := m.(type) {
+	case *dynamic.Message:
+		buf, err := t.MarshalJSONPB(&jsonpb.Marshaler{OrigName: true, EmitDefaults: true})
+		if err != nil {
+			return nil, err
+		}
+		var data interface{}
+		if err := json.Unmarshal(buf, &data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	case *emptypb.Empty:
+		return map[string]interface{}{}, nil
+	default:
+		m, err := dynamic.AsDynamicMessage(t)
+		if err != nil {
+			return nil, err
+		}
+		return c.getMessage(m)
+	}
+}
+
 func (c *Client) getArgument(field *entity.Field, data interface{}) (interface{}, error) {
 	if isEmpty(data) {
 		return nil, nil
@@ -494,64 +633,45 @@ func getProtoFQN(f *entity.Field) string {
 	return f.FQN
 }
 
-func (c *Client) request(ms *dynamic.Message) {
-	c.sentMessages++
-	c.requestCh <- ms
-}
-
-func (c *Client) response(data proto.Message, header metadata.MD, trailer metadata.MD, err error) {
-	spent := time.Since(c.queryStartTime).String()
+func (c *Client) response(s *session, data proto.Message, header metadata.MD, trailer metadata.MD, err error) {
+	spent := time.Since(s.startTime).String()
 
 	if err != nil {
-		c.responseError(err, spent)
+		c.responseError(s, err, spent)
 		return
 	}
 
 	if data != nil {
-		c.receivedMessaged++
+		s.received.Add(1)
 	}
 
 	resp := &entity.QueryResponse{
+		SessionID: s.id,
 		Time:      time.Now().Format("15:04:05.99"),
 		SpentTime: spent,
 		Header:    header,
 		Trailer:   trailer,
 		Error:     toError(err),
-		Sent:      c.sentMessages,
-		Received:  c.receivedMessaged,
+		Sent:      uint(s.sent.Load()),
+		Received:  uint(s.received.Load()),
+		Attempts:  getAttempts(s.ctx),
 	}
 
 	resp.JsonString, _ = c.getResponse(data)
+	resp.Message, _ = c.getMessage(data)
 
 	c.responseCh <- resp
 }
 
-func (c *Client) responseError(err error, spent string) {
+func (c *Client) responseError(s *session, err error, spent string) {
 	c.responseCh <- &entity.QueryResponse{
+		SessionID: s.id,
 		Error:     toError(err),
 		SpentTime: spent,
+		Attempts:  getAttempts(s.ctx),
 	}
 }
 
-func (c *Client) startRequest() bool {
-	if c.requestCh != nil {
-		return false
-	}
-
-	c.requestCh = make(chan *dynamic.Message, requestChanCapacity)
-	c.sentMessages = 0
-	c.receivedMessaged = 0
-
-	return true
-}
-
-func (c *Client) stopRequest() {
-	close(c.requestCh)
-	c.requestCh = nil
-	c.sentMessages = 0
-	c.receivedMessaged = 0
-}
-
 func toError(err error) *entity.Error {
 	if err == nil {
 		return nil
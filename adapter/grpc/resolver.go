@@ -0,0 +1,167 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	resolverScheme       = "warthog"
+	resolverTimeout      = 10 * time.Second
+	roundRobinLBConfig   = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+	resolverCommentsChar = "#"
+)
+
+// resolverBuilder builds the resolver of the server backends, the target endpoint is the server address.
+type resolverBuilder struct {
+	cfg     *entity.Resolver
+	onError func(err error)
+}
+
+// addrResolver resolves the server address by the static list, DNS SRV record or file and applies the host overrides.
+type addrResolver struct {
+	cfg     *entity.Resolver
+	addr    string
+	cc      resolver.ClientConn
+	ctx     context.Context
+	cancel  context.CancelFunc
+	onError func(err error)
+	mux     sync.Mutex
+}
+
+// getResolverTarget returns the dial target resolved by the resolver, the server address is kept as the authority.
+func getResolverTarget(addr string) string {
+	return resolverScheme + ":///" + addr
+}
+
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &addrResolver{
+		cfg:     b.cfg,
+		addr:    target.Endpoint(),
+		cc:      cc,
+		ctx:     ctx,
+		cancel:  cancel,
+		onError: b.onError,
+	}
+	if err := r.update(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (b *resolverBuilder) Scheme() string {
+	return resolverScheme
+}
+
+// ResolveNow resolves the addresses again, it is called by gRPC on reconnect.
+func (r *addrResolver) ResolveNow(resolver.ResolveNowOptions) {
+	if err := r.update(); err != nil {
+		if r.onError != nil {
+			r.onError(err)
+		}
+		r.cc.ReportError(err)
+	}
+}
+
+func (r *addrResolver) update() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	addrs, err := r.resolve()
+	if err != nil {
+		return err
+	}
+
+	state := resolver.State{Addresses: make([]resolver.Address, 0, len(addrs))}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: r.cfg.Override(addr)})
+	}
+
+	// the error is returned when the balancer rejects the addresses, gRPC calls ResolveNow with backoff
+	_ = r.cc.UpdateState(state)
+
+	return nil
+}
+
+func (r *addrResolver) Close() {
+	r.cancel()
+}
+
+func (r *addrResolver) resolve() ([]string, error) {
+	switch r.cfg.Type {
+	case entity.ResolverTypeStatic:
+		return r.cfg.Addresses, nil
+	case entity.ResolverTypeDNSSRV:
+		return r.lookupSRV()
+	case entity.ResolverTypeFile:
+		return readResolverFile(r.cfg.File)
+	default:
+		return []string{r.addr}, nil
+	}
+}
+
+func (r *addrResolver) lookupSRV() ([]string, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, resolverTimeout)
+	defer cancel()
+
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", r.cfg.SRVName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup SRV record %s: %v", r.cfg.SRVName, err)
+	}
+
+	addrs := make([]string, 0, len(records))
+	for _, rec := range records {
+		addrs = append(addrs, net.JoinHostPort(strings.TrimSuffix(rec.Target, "."), strconv.Itoa(int(rec.Port))))
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no targets of SRV record %s", r.cfg.SRVName)
+	}
+
+	return addrs, nil
+}
+
+// readResolverFile reads host:port per line, the empty lines and the lines starting with # are skipped.
+func readResolverFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var addrs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, resolverCommentsChar) {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(line); err != nil {
+			return nil, fmt.Errorf("wrong backend address %s in %s: %v", line, path, err)
+		}
+		addrs = append(addrs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no backends in %s", path)
+	}
+
+	return addrs, nil
+}
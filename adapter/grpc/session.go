@@ -0,0 +1,145 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+// session call of the method, the sessions run concurrently on the connection they were started on
+// and their responses are tagged with the session ID.
+type session struct {
+	id        uint64
+	method    *entity.Method
+	conn      *grpc.ClientConn
+	web       *webTransport
+	ctx       context.Context
+	cancel    context.CancelFunc
+	startTime time.Time
+	requestCh chan *dynamic.Message
+	closeCh   chan struct{}
+	done      chan struct{}
+	doneOnce  sync.Once
+	sent      atomic.Uint64
+	received  atomic.Uint64
+}
+
+// isClientStream checks whether the messages of the next queries are sent to the session.
+func (s *session) isClientStream() bool {
+	return s.method.Type == entity.MethodTypeClientStream || s.method.Type == entity.MethodTypeBidiStream
+}
+
+// isDone checks whether the session is finished.
+func (s *session) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// request queues the message of the client stream.
+func (s *session) request(ms *dynamic.Message) {
+	select {
+	case s.requestCh <- ms:
+		s.sent.Add(1)
+	case <-s.done:
+	}
+}
+
+// sendPending sends messages queued before the stream was closed.
+func (s *session) sendPending(stream interface{ SendMsg(m proto.Message) error }) error {
+	for {
+		select {
+		case ms := <-s.requestCh:
+			if ms == nil {
+				continue
+			}
+			if err := stream.SendMsg(ms); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// newSession starts the session of the method on the current connection.
+func (c *Client) newSession(id uint64, method *entity.Method, metadata []string) *session {
+	s := &session{
+		id:        id,
+		method:    method,
+		requestCh: make(chan *dynamic.Message, requestChanCapacity),
+		closeCh:   make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		startTime: time.Now(),
+	}
+
+	if timeout := *c.settings().RequestTimeout; timeout > 0 && method.Type == entity.MethodTypeUnary {
+		s.ctx, s.cancel = context.WithTimeout(c.ctx, time.Second*time.Duration(timeout))
+	} else {
+		s.ctx, s.cancel = context.WithCancel(c.ctx)
+	}
+	s.ctx = withAttempts(addMetadata(s.ctx, metadata))
+
+	c.connectionMux.Lock()
+	s.conn, s.web = c.conn, c.web
+	c.sessions[id] = s
+	c.connectionMux.Unlock()
+
+	return s
+}
+
+// getSession returns the running session.
+func (c *Client) getSession(id uint64) *session {
+	c.connectionMux.RLock()
+	defer c.connectionMux.RUnlock()
+
+	s, ok := c.sessions[id]
+	if !ok || s.isDone() {
+		return nil
+	}
+	return s
+}
+
+// getSessions returns the running sessions, all of them if the ID is zero.
+func (c *Client) getSessions(id uint64) []*session {
+	if id != 0 {
+		if s := c.getSession(id); s != nil {
+			return []*session{s}
+		}
+		return nil
+	}
+
+	c.connectionMux.RLock()
+	defer c.connectionMux.RUnlock()
+
+	sessions := make([]*session, 0, len(c.sessions))
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// endSession removes the finished session and closes the connection released while the session was running.
+func (c *Client) endSession(s *session) {
+	s.doneOnce.Do(func() {
+		close(s.done)
+		s.cancel()
+
+		c.connectionMux.Lock()
+		defer c.connectionMux.Unlock()
+
+		delete(c.sessions, s.id)
+		c.closeUnused(s.conn, s.web)
+	})
+}
@@ -0,0 +1,644 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/structs"
+)
+
+var pointerHelpers = map[descriptorpb.FieldDescriptorProto_Type]string{
+	descriptorpb.FieldDescriptorProto_TYPE_BOOL:     "Bool",
+	descriptorpb.FieldDescriptorProto_TYPE_STRING:   "String",
+	descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:   "Float64",
+	descriptorpb.FieldDescriptorProto_TYPE_FLOAT:    "Float32",
+	descriptorpb.FieldDescriptorProto_TYPE_INT32:    "Int32",
+	descriptorpb.FieldDescriptorProto_TYPE_SINT32:   "Int32",
+	descriptorpb.FieldDescriptorProto_TYPE_SFIXED32: "Int32",
+	descriptorpb.FieldDescriptorProto_TYPE_INT64:    "Int64",
+	descriptorpb.FieldDescriptorProto_TYPE_SINT64:   "Int64",
+	descriptorpb.FieldDescriptorProto_TYPE_SFIXED64: "Int64",
+	descriptorpb.FieldDescriptorProto_TYPE_UINT32:   "Uint32",
+	descriptorpb.FieldDescriptorProto_TYPE_FIXED32:  "Uint32",
+	descriptorpb.FieldDescriptorProto_TYPE_UINT64:   "Uint64",
+	descriptorpb.FieldDescriptorProto_TYPE_FIXED64:  "Uint64",
+}
+
+// snippet Go client code builder, the messages are referenced by the packages generated by protoc-gen-go.
+type snippet struct {
+	imports  map[string]string
+	aliases  map[string]string
+	warnings []string
+	body     strings.Builder
+}
+
+// GoSnippet generates the Go client code executing the request with the same connection options.
+func (c *Client) GoSnippet(method *entity.Method, data map[string]interface{}, server *entity.WorkspaceItemServer, metadata []string) (*entity.CodeSnippet, error) {
+	ms, err := c.newMessage(method, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snippet{
+		imports: make(map[string]string, 16),
+		aliases: make(map[string]string, 16),
+	}
+	files := server.CertificateFiles()
+	if files == nil {
+		files = make(map[string]string, 1)
+	}
+
+	s.transport(server, files)
+	s.overrides(server)
+	s.perRPC(server.Auth, files)
+	s.connect(server.Addr)
+	s.context(server.Auth, metadata)
+	s.printf("\nreq := %s\n", s.message(ms))
+	s.call(method)
+
+	var src strings.Builder
+	src.WriteString("package main\n\nimport (\n")
+	for _, std := range []bool{true, false} {
+		for p, alias := range s.imports {
+			if std != !strings.Contains(strings.Split(p, "/")[0], ".") {
+				continue
+			}
+			if alias == path.Base(p) {
+				fmt.Fprintf(&src, "%q\n", p)
+			} else {
+				fmt.Fprintf(&src, "%s %q\n", alias, p)
+			}
+		}
+		src.WriteString("\n")
+	}
+	src.WriteString(")\n\nfunc main() {\n")
+	src.WriteString(s.body.String())
+	src.WriteString("}\n")
+
+	code, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	if !server.IsNativeTransport() {
+		s.warnings = append(s.warnings, fmt.Sprintf("the code uses the native gRPC instead of the %s transport", server.Transport))
+	}
+	if server.Proxy.IsEnabled() {
+		s.warnings = append(s.warnings, fmt.Sprintf("the server is reached through the %s proxy %s, add grpc.WithContextDialer to dial it", server.Proxy.Type, server.Proxy.Addr))
+	}
+	if server.IsSSHEnabled() {
+		s.warnings = append(s.warnings, fmt.Sprintf("the server is reached through the SSH tunnel to %s, open the tunnel before running the code", server.SSHTunnel.RemoteAddr))
+	}
+	if server.IsK8SEnabled() {
+		s.warnings = append(s.warnings, "the server is reached through the Kubernetes port forwarding, forward the port before running the code")
+	}
+	if server.Resolver.IsEnabled() {
+		s.warnings = append(s.warnings, "the resolver is not exported, the code connects to the server address")
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.warnings = append(s.warnings, fmt.Sprintf("save the credentials to %s", name))
+	}
+
+	return &entity.CodeSnippet{
+		Code:     string(code),
+		Files:    files,
+		Warnings: s.warnings,
+	}, nil
+}
+
+func (s *snippet) transport(server *entity.WorkspaceItemServer, files map[string]string) {
+	grpcPkg := s.use("google.golang.org/grpc")
+
+	if server.NoTLS {
+		s.printf("opts := []%[1]s.DialOption{%[1]s.WithTransportCredentials(%[2]s.NewCredentials())}\n",
+			grpcPkg, s.use("google.golang.org/grpc/credentials/insecure"))
+	} else {
+		tlsPkg := s.use("crypto/tls")
+		fields := make([]string, 0, 2)
+		if server.ServerName != "" {
+			fields = append(fields, fmt.Sprintf("ServerName: %q", server.ServerName))
+		}
+		if server.MinTLSVersion != "" {
+			fields = append(fields, fmt.Sprintf("MinVersion: %s.%s", tlsPkg, tlsVersionConst(server.MinTLSVersion)))
+		}
+		if server.MaxTLSVersion != "" {
+			fields = append(fields, fmt.Sprintf("MaxVersion: %s.%s", tlsPkg, tlsVersionConst(server.MaxTLSVersion)))
+		}
+		if len(server.CipherSuites) > 0 {
+			suites := structs.Map(server.CipherSuites, func(name string) string { return tlsPkg + "." + name })
+			fields = append(fields, fmt.Sprintf("CipherSuites: []uint16{%s}", strings.Join(suites, ", ")))
+		}
+		if len(server.ALPN) > 0 {
+			fields = append(fields, fmt.Sprintf("NextProtos: %#v", server.ALPN))
+		}
+		if server.Insecure {
+			fields = append(fields, "InsecureSkipVerify: true")
+			s.printf("tlsConfig := &%s.Config{%s} // nolint:gosec\n", tlsPkg, strings.Join(fields, ", "))
+		} else {
+			s.printf("tlsConfig := &%s.Config{%s}\n", tlsPkg, strings.Join(fields, ", "))
+		}
+		caFile := server.RootCertificatePath
+		if _, ok := files[entity.RootCertificateFile]; ok {
+			caFile = entity.RootCertificateFile
+		}
+		if caFile != "" {
+			pool := s.use("crypto/x509") + ".NewCertPool()"
+			if server.UseSystemRoots {
+				s.printf(`
+pool, err := %[1]s.SystemCertPool()
+if err != nil {
+	%[2]s.Fatalf("failed to load system CA pool: %%v", err)
+}
+`, s.use("crypto/x509"), s.use("log"))
+				pool = "pool"
+			}
+			s.printf(`
+ca, err := %[1]s.ReadFile(%[2]q)
+if err != nil {
+	%[3]s.Fatalf("failed to read server CA's certificate: %%v", err)
+}
+tlsConfig.RootCAs = %[4]s
+if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+	%[3]s.Fatal("failed to add server CA's certificate")
+}
+`, s.use("os"), caFile, s.use("log"), pool)
+		}
+		certFile, keyFile := server.ClientCertificatePath, server.ClientKeyPath
+		if _, ok := files[entity.ClientCertificateFile]; ok {
+			certFile, keyFile = entity.ClientCertificateFile, entity.ClientKeyFile
+		}
+		if server.PKCS12Path != "" {
+			s.warnings = append(s.warnings, "PKCS#12 bundles are not exported, convert the bundle with openssl pkcs12 -nodes to the certificate and key files")
+		} else if certFile != "" && keyFile != "" {
+			s.printf(`
+cert, err := %[1]s.LoadX509KeyPair(%[2]q, %[3]q)
+if err != nil {
+	%[4]s.Fatalf("failed to load client certificate: %%v", err)
+}
+tlsConfig.Certificates = []%[1]s.Certificate{cert}
+`, tlsPkg, certFile, keyFile, s.use("log"))
+		}
+		s.printf("\nopts := []%[1]s.DialOption{%[1]s.WithTransportCredentials(%[2]s.NewTLS(tlsConfig))}\n",
+			grpcPkg, s.use("google.golang.org/grpc/credentials"))
+	}
+}
+
+func tlsVersionConst(version string) string {
+	return "VersionTLS1" + strings.TrimPrefix(version, "1.")
+}
+
+func (s *snippet) overrides(server *entity.WorkspaceItemServer) {
+	if server.Authority != "" {
+		s.printf("opts = append(opts, %s.WithAuthority(%q))\n", s.use("google.golang.org/grpc"), server.Authority)
+	}
+	if server.UserAgent != "" {
+		s.printf("opts = append(opts, %s.WithUserAgent(%q))\n", s.use("google.golang.org/grpc"), server.UserAgent)
+	}
+	if server.ServiceConfig != "" {
+		cfg := &bytes.Buffer{}
+		if err := json.Compact(cfg, []byte(server.ServiceConfig)); err != nil {
+			cfg.Reset()
+			cfg.WriteString(server.ServiceConfig)
+		}
+		s.printf("opts = append(opts, %s.WithDefaultServiceConfig(%q))\n", s.use("google.golang.org/grpc"), cfg.String())
+	}
+}
+
+func (s *snippet) perRPC(auth *entity.Auth, files map[string]string) {
+	if auth == nil || auth.Type != entity.AuthTypeGCE {
+		return
+	}
+
+	files[entity.ServiceAccountFile] = auth.GoogleToken
+	scopes := make([]string, 0, len(auth.GoogleScopes))
+	for _, scope := range auth.GoogleScopes {
+		scopes = append(scopes, strconv.Quote(scope))
+	}
+
+	s.printf(`
+perRPC, err := %[1]s.NewServiceAccountFromFile(%[2]q, %[3]s)
+if err != nil {
+	%[4]s.Fatalf("failed to load service account: %%v", err)
+}
+opts = append(opts, %[5]s.WithPerRPCCredentials(perRPC))
+`, s.use("google.golang.org/grpc/credentials/oauth"), entity.ServiceAccountFile, strings.Join(scopes, ", "),
+		s.use("log"), s.use("google.golang.org/grpc"))
+}
+
+func (s *snippet) connect(addr string) {
+	s.printf(`
+conn, err := %[1]s.NewClient(%[2]q, opts...)
+if err != nil {
+	%[3]s.Fatalf("failed to connect: %%v", err)
+}
+defer conn.Close()
+`, s.use("google.golang.org/grpc"), addr, s.use("log"))
+}
+
+func (s *snippet) context(auth *entity.Auth, metadata []string) {
+	s.printf("\nctx := %s.Background()\n", s.use("context"))
+
+	if auth != nil {
+		s.authorization(auth)
+	}
+	s.metadata(metadata)
+}
+
+func (s *snippet) authorization(auth *entity.Auth) {
+	var header string
+	switch auth.Type {
+	case entity.AuthTypeBasic:
+		header = fmt.Sprintf(`"Basic "+%s.StdEncoding.EncodeToString([]byte(%q))`, s.use("encoding/base64"), auth.Login+":"+auth.Password)
+	case entity.AuthTypeBearer:
+		header = strconv.Quote(headerPrefix(auth) + " " + auth.Token)
+	case entity.AuthTypeJWT:
+		token, err := signJWT(auth)
+		if err != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("failed to sign JWT: %v", err))
+			return
+		}
+		s.printf("// the token is signed by Warthog, sign a new one when it expires\n")
+		header = strconv.Quote(headerPrefix(auth) + " " + token)
+	default:
+		return
+	}
+
+	s.printf("ctx = %s.AppendToOutgoingContext(ctx, \"authorization\", %s)\n", s.use("google.golang.org/grpc/metadata"), header)
+}
+
+// headerPrefix returns the prefix of the authorization header, the empty one is Bearer as in the oauth2 token.
+func headerPrefix(auth *entity.Auth) string {
+	return (&oauth2.Token{TokenType: auth.HeaderPrefix}).Type()
+}
+
+func (s *snippet) metadata(metadata []string) {
+	if len(metadata) < 2 {
+		return
+	}
+
+	pairs := make([]string, 0, len(metadata))
+	for i := 0; i+1 < len(metadata); i += 2 {
+		pairs = append(pairs, strconv.Quote(metadata[i])+", "+strconv.Quote(metadata[i+1]))
+	}
+
+	s.printf("ctx = %s.AppendToOutgoingContext(ctx,\n%s,\n)\n", s.use("google.golang.org/grpc/metadata"), strings.Join(pairs, ",\n"))
+}
+
+func (s *snippet) call(method *entity.Method) {
+	md := method.Descriptor
+	sd := md.GetService()
+	client := s.qualify(sd.GetFile(), "New"+goCamelCase(sd.GetName())+"Client")
+	name := goCamelCase(md.GetName())
+	logPkg := s.use("log")
+	printResp := s.use("fmt") + ".Println(" + s.use("google.golang.org/protobuf/encoding/protojson") + ".Format(resp))"
+
+	s.printf("\nclient := %s(conn)\n", client)
+
+	switch method.Type {
+	case entity.MethodTypeUnary:
+		s.printf(`
+resp, err := client.%[1]s(ctx, req)
+if err != nil {
+	%[2]s.Fatalf("request failed: %%v", err)
+}
+%[3]s
+`, name, logPkg, printResp)
+	case entity.MethodTypeServerStream:
+		s.printf(`
+stream, err := client.%[1]s(ctx, req)
+if err != nil {
+	%[2]s.Fatalf("request failed: %%v", err)
+}
+`, name, logPkg)
+		s.receive(logPkg, printResp)
+	case entity.MethodTypeClientStream:
+		s.printf(`
+stream, err := client.%[1]s(ctx)
+if err != nil {
+	%[2]s.Fatalf("request failed: %%v", err)
+}
+if err := stream.Send(req); err != nil {
+	%[2]s.Fatalf("failed to send: %%v", err)
+}
+resp, err := stream.CloseAndRecv()
+if err != nil {
+	%[2]s.Fatalf("request failed: %%v", err)
+}
+%[3]s
+`, name, logPkg, printResp)
+	case entity.MethodTypeBidiStream:
+		s.printf(`
+stream, err := client.%[1]s(ctx)
+if err != nil {
+	%[2]s.Fatalf("request failed: %%v", err)
+}
+if err := stream.Send(req); err != nil {
+	%[2]s.Fatalf("failed to send: %%v", err)
+}
+if err := stream.CloseSend(); err != nil {
+	%[2]s.Fatalf("failed to close stream: %%v", err)
+}
+`, name, logPkg)
+		s.receive(logPkg, printResp)
+	}
+}
+
+func (s *snippet) receive(logPkg, printResp string) {
+	s.printf(`for {
+	resp, err := stream.Recv()
+	if %[1]s.Is(err, %[2]s.EOF) {
+		break
+	}
+	if err != nil {
+		%[3]s.Fatalf("failed to receive: %%v", err)
+	}
+	%[4]s
+}
+`, s.use("errors"), s.use("io"), logPkg, printResp)
+}
+
+func (s *snippet) message(ms *dynamic.Message) string {
+	md := ms.GetMessageDescriptor()
+
+	var b strings.Builder
+	b.WriteString("&" + s.typeName(md.GetFile(), md.GetFullyQualifiedName()) + "{")
+	for _, fd := range md.GetFields() {
+		if !ms.HasField(fd) {
+			continue
+		}
+		v := ms.GetField(fd)
+		if oo := fd.GetOneOf(); oo != nil && !fd.IsProto3Optional() {
+			wrapper := s.typeName(md.GetFile(), md.GetFullyQualifiedName()) + "_" + goCamelCase(fd.GetName())
+			fmt.Fprintf(&b, "\n%s: &%s{%s: %s},", goCamelCase(oo.GetName()), wrapper, goCamelCase(fd.GetName()), s.value(fd, v))
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s: %s,", goCamelCase(fd.GetName()), s.fieldValue(fd, v))
+	}
+	if len(md.GetFields()) > 0 && strings.HasSuffix(b.String(), ",") {
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func (s *snippet) fieldValue(fd *desc.FieldDescriptor, v interface{}) string {
+	switch {
+	case fd.IsMap():
+		items, _ := v.(map[interface{}]interface{})
+		keys := make([]interface{}, 0, len(items))
+		for k := range items {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "map[%s]%s{", s.goType(fd.GetMapKeyType()), s.goType(fd.GetMapValueType()))
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\n%s: %s,", s.value(fd.GetMapKeyType(), k), s.element(fd.GetMapValueType(), items[k]))
+		}
+		if len(keys) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+		return b.String()
+	case fd.IsRepeated():
+		items, _ := v.([]interface{})
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, s.element(fd, item))
+		}
+		if len(values) == 0 {
+			return "[]" + s.goType(fd) + "{}"
+		}
+		return "[]" + s.goType(fd) + "{\n" + strings.Join(values, ",\n") + ",\n}"
+	case fd.IsProto3Optional() || (!fd.GetFile().IsProto3() && fd.GetMessageType() == nil &&
+		fd.GetType() != descriptorpb.FieldDescriptorProto_TYPE_BYTES):
+		if fd.GetEnumType() != nil {
+			return s.value(fd, v) + ".Enum()"
+		}
+		return s.use("google.golang.org/protobuf/proto") + "." + pointerHelpers[fd.GetType()] + "(" + s.value(fd, v) + ")"
+	default:
+		return s.value(fd, v)
+	}
+}
+
+// element returns the value of the slice or map element, the type of the message literals is elided.
+func (s *snippet) element(fd *desc.FieldDescriptor, v interface{}) string {
+	value := s.value(fd, v)
+	if md := fd.GetMessageType(); md != nil {
+		return strings.TrimPrefix(value, "&"+s.typeName(md.GetFile(), md.GetFullyQualifiedName()))
+	}
+	return value
+}
+
+func (s *snippet) value(fd *desc.FieldDescriptor, v interface{}) string {
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		str, _ := v.(string)
+		return strconv.Quote(str)
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		data, _ := v.([]byte)
+		return "[]byte(" + strconv.Quote(string(data)) + ")"
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		f, _ := v.(float64)
+		return s.float(f, 64)
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		f, _ := v.(float32)
+		return s.float(float64(f), 32)
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		ed := fd.GetEnumType()
+		n, _ := v.(int32)
+		if vd := ed.FindValueByNumber(n); vd != nil {
+			return s.qualify(ed.GetFile(), enumValuePrefix(ed)+"_"+vd.GetName())
+		}
+		return fmt.Sprintf("%s(%d)", s.typeName(ed.GetFile(), ed.GetFullyQualifiedName()), n)
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		m, _ := v.(proto.Message)
+		dm, err := dynamic.AsDynamicMessage(m)
+		if err != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("failed to convert field %s: %v", fd.GetName(), err))
+			return "nil"
+		}
+		return s.message(dm)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (s *snippet) float(f float64, bits int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return s.use("math") + ".Inf(1)"
+	case math.IsInf(f, -1):
+		return s.use("math") + ".Inf(-1)"
+	case math.IsNaN(f):
+		return s.use("math") + ".NaN()"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits)
+}
+
+func (s *snippet) goType(fd *desc.FieldDescriptor) string {
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		md := fd.GetMessageType()
+		return "*" + s.typeName(md.GetFile(), md.GetFullyQualifiedName())
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		ed := fd.GetEnumType()
+		return s.typeName(ed.GetFile(), ed.GetFullyQualifiedName())
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "[]byte"
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return "string"
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return "bool"
+	}
+	return strings.ToLower(pointerHelpers[fd.GetType()])
+}
+
+// typeName returns the qualified Go name of the message or enum type.
+func (s *snippet) typeName(fd *desc.FileDescriptor, fqn string) string {
+	name := fqn
+	if pkg := fd.GetPackage(); pkg != "" {
+		name = strings.TrimPrefix(fqn, pkg+".")
+	}
+	return s.qualify(fd, goCamelCase(name))
+}
+
+// qualify returns the identifier qualified by the Go package of the file.
+func (s *snippet) qualify(fd *desc.FileDescriptor, ident string) string {
+	importPath, name := fd.GetFileOptions().GetGoPackage(), ""
+	if idx := strings.IndexByte(importPath, ';'); idx >= 0 {
+		importPath, name = importPath[:idx], importPath[idx+1:]
+	}
+
+	switch {
+	case importPath == "":
+		pkg := fd.GetPackage()
+		if pkg == "" {
+			pkg = strings.TrimSuffix(path.Base(fd.GetName()), path.Ext(fd.GetName()))
+		}
+		importPath = "example.com/" + strings.ReplaceAll(pkg, ".", "/")
+		s.warn(fmt.Sprintf("%s has no go_package option, replace the import path %s", fd.GetName(), importPath))
+	case strings.HasPrefix(importPath, "."):
+		s.warn(fmt.Sprintf("%s has the relative go_package %s, replace it with the import path of the generated code", fd.GetName(), importPath))
+	}
+
+	if name == "" {
+		name = path.Base(importPath)
+	}
+
+	return s.useAs(importPath, goIdentifier(name)) + "." + ident
+}
+
+func (s *snippet) use(importPath string) string {
+	return s.useAs(importPath, path.Base(importPath))
+}
+
+func (s *snippet) useAs(importPath, name string) string {
+	if alias, ok := s.imports[importPath]; ok {
+		return alias
+	}
+
+	alias := name
+	for i := 2; ; i++ {
+		if _, ok := s.aliases[alias]; !ok {
+			break
+		}
+		alias = name + strconv.Itoa(i)
+	}
+
+	s.imports[importPath] = alias
+	s.aliases[alias] = importPath
+
+	return alias
+}
+
+func (s *snippet) warn(w string) {
+	for _, exists := range s.warnings {
+		if exists == w {
+			return
+		}
+	}
+	s.warnings = append(s.warnings, w)
+}
+
+func (s *snippet) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&s.body, format, args...)
+}
+
+// enumValuePrefix returns the prefix of the enum value constants, protoc-gen-go prefixes
+// the values of the nested enums by the parent message name.
+func enumValuePrefix(ed *desc.EnumDescriptor) string {
+	name := ed.GetFullyQualifiedName()
+	if md, ok := ed.GetParent().(*desc.MessageDescriptor); ok {
+		name = md.GetFullyQualifiedName()
+	}
+	if pkg := ed.GetFile().GetPackage(); pkg != "" {
+		name = strings.TrimPrefix(name, pkg+".")
+	}
+	return goCamelCase(name)
+}
+
+// goCamelCase converts the protobuf name into the Go name the same way protoc-gen-go does.
+func goCamelCase(s string) string {
+	isLower := func(c byte) bool { return 'a' <= c && c <= 'z' }
+	isDigit := func(c byte) bool { return '0' <= c && c <= '9' }
+
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isLower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isLower(s[i+1]):
+		case isDigit(c):
+			b = append(b, c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isLower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+
+	return string(b)
+}
+
+func goIdentifier(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_') {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 || '0' <= b[0] && b[0] <= '9' {
+		b = append([]byte{'_'}, b...)
+	}
+	return string(b)
+}
@@ -0,0 +1,199 @@
+package grpc
+
+import (
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/logger"
+)
+
+const snippetProto = `syntax = "proto3";
+
+package echo.v1;
+
+option go_package = "example.com/echo/v1;echov1";
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_OK = 1;
+}
+
+message Request {
+  string name = 1;
+  int32 count = 2;
+  Status status = 3;
+  repeated string tags = 4;
+  map<string, string> labels = 5;
+}
+
+message Response {
+  string message = 1;
+}
+
+service Echo {
+  rpc Unary(Request) returns (Response);
+  rpc ServerStream(Request) returns (stream Response);
+  rpc ClientStream(stream Request) returns (Response);
+  rpc Bidi(stream Request) returns (stream Response);
+}
+`
+
+// snippetStub the code generated by protoc-gen-go and protoc-gen-go-grpc for snippetProto.
+const snippetStub = `package echov1
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_OK          Status = 1
+)
+
+type Request struct {
+	Name   string
+	Count  int32
+	Status Status
+	Tags   []string
+	Labels map[string]string
+}
+
+func (*Request) ProtoReflect() protoreflect.Message { return nil }
+
+type Response struct {
+	Message string
+}
+
+func (*Response) ProtoReflect() protoreflect.Message { return nil }
+
+type EchoClient interface {
+	Unary(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	ServerStream(ctx context.Context, in *Request, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Response], error)
+	ClientStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Request, Response], error)
+	Bidi(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Request, Response], error)
+}
+
+func NewEchoClient(cc grpc.ClientConnInterface) EchoClient { return nil }
+`
+
+// stubImporter imports the stub packages of the go_package options, the rest is imported from the source.
+type stubImporter struct {
+	fset  *token.FileSet
+	stubs map[string]string
+	pkgs  map[string]*types.Package
+	src   types.ImporterFrom
+}
+
+func (i *stubImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, "", 0)
+}
+
+func (i *stubImporter) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if pkg, ok := i.pkgs[path]; ok {
+		return pkg, nil
+	}
+	stub, ok := i.stubs[path]
+	if !ok {
+		return i.src.ImportFrom(path, dir, mode)
+	}
+
+	pkg, err := i.check(path, filepath.Join(dir, filepath.Base(path)+".go"), stub)
+	if err != nil {
+		return nil, err
+	}
+	i.pkgs[path] = pkg
+
+	return pkg, nil
+}
+
+func (i *stubImporter) check(path, name, src string) (*types.Package, error) {
+	f, err := parser.ParseFile(i.fset, name, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	conf := types.Config{Importer: i}
+	return conf.Check(path, i.fset, []*ast.File{f}, nil)
+}
+
+func TestGoSnippetTypeCheck(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "echo.proto"), []byte(snippetProto), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := *entity.DefaultSettings
+	c := New(context.Background(), logger.NewDefaultZerolog())
+	c.SetSettings(&settings)
+	c.AddImport(dir)
+	c.AddProtobuf(filepath.Join(dir, "echo.proto"))
+
+	services, _, protoErr := c.LoadFromProtobuf()
+	if protoErr != nil {
+		t.Fatalf("failed to load protobuf: %v", protoErr.Err)
+	}
+	if len(services) != 1 || len(services[0].Methods) != 4 {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	// the snippet is checked in the package directory to resolve the imports of the module
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	imp := &stubImporter{
+		fset:  fset,
+		stubs: map[string]string{"example.com/echo/v1": snippetStub},
+		pkgs:  make(map[string]*types.Package, 1),
+		src:   importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+	}
+
+	values := map[string]interface{}{
+		"name":   "warthog",
+		"count":  "42",
+		"status": "1",
+		"tags":   []interface{}{"a", "b"},
+		"labels": map[string]interface{}{"env": "test"},
+	}
+	server := &entity.WorkspaceItemServer{
+		Addr: "localhost:50051",
+		Auth: &entity.Auth{Type: entity.AuthTypeBearer, Token: "token"},
+	}
+
+	for _, method := range services[0].Methods {
+		t.Run(method.Name, func(t *testing.T) {
+			data := make(map[string]interface{}, len(values))
+			for _, f := range method.Input {
+				if v, ok := values[f.Name]; ok {
+					data[getProtoFQN(f)] = v
+				}
+			}
+
+			snippet, err := c.GoSnippet(method, data, server, []string{"x-request-id", "1"})
+			if err != nil {
+				t.Fatalf("failed to generate snippet: %v", err)
+			}
+			if !strings.Contains(snippet.Code, `"Bearer token"`) {
+				t.Errorf("the empty header prefix is not Bearer:\n%s", snippet.Code)
+			}
+
+			if _, err := imp.check("main", filepath.Join(wd, "main.go"), snippet.Code); err != nil {
+				t.Fatalf("snippet does not compile: %v\n%s", err, snippet.Code)
+			}
+		})
+	}
+}
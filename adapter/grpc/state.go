@@ -0,0 +1,123 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const minConnectTimeout = 20 * time.Second
+
+// StateHandler receives the connectivity state transitions of the connection.
+type StateHandler func(state *entity.ConnectionState)
+
+// errorCredentials records the TLS handshake errors of the connection attempts.
+type errorCredentials struct {
+	credentials.TransportCredentials
+	cn *connection
+}
+
+func (e *errorCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	secure, info, err := e.TransportCredentials.ClientHandshake(ctx, authority, conn)
+	if err != nil {
+		e.cn.setError(err)
+	}
+	return secure, info, err
+}
+
+func (e *errorCredentials) Clone() credentials.TransportCredentials {
+	return &errorCredentials{TransportCredentials: e.TransportCredentials.Clone(), cn: e.cn}
+}
+
+// getConnectParams returns the reconnect backoff from the settings, the gRPC defaults are used for the zero values.
+func (c *Client) getConnectParams() grpc.DialOption {
+	settings := c.settings()
+
+	cfg := backoff.DefaultConfig
+	if settings != nil && settings.ReconnectBaseDelay != nil && *settings.ReconnectBaseDelay > 0 {
+		cfg.BaseDelay = time.Duration(*settings.ReconnectBaseDelay) * time.Second
+	}
+	if settings != nil && settings.ReconnectMaxDelay != nil && *settings.ReconnectMaxDelay > 0 {
+		cfg.MaxDelay = time.Duration(*settings.ReconnectMaxDelay) * time.Second
+	}
+	if cfg.BaseDelay > cfg.MaxDelay {
+		cfg.BaseDelay = cfg.MaxDelay
+	}
+
+	return grpc.WithConnectParams(grpc.ConnectParams{
+		Backoff:           cfg,
+		MinConnectTimeout: minConnectTimeout,
+	})
+}
+
+// getErrorDialer returns the dialer recording the errors of the connection attempts.
+// The default gRPC dialer is kept for Unix sockets and for the proxy from the environment.
+func (c *Client) getErrorDialer(cn *connection, addr string, dialer contextDialer) contextDialer {
+	if dialer == nil {
+		if _, ok := entity.UnixSocketPath(addr); ok || isEnvProxy() {
+			return nil
+		}
+		d := &net.Dialer{}
+		dialer = func(ctx context.Context, addr string) (net.Conn, error) {
+			return d.DialContext(ctx, "tcp", addr)
+		}
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		conn, err := dialer(ctx, addr)
+		if err != nil {
+			cn.setError(err)
+		}
+		return conn, err
+	}
+}
+
+// watchState sends the state transitions of the connection to the handler until the connection is closed,
+// the idle connection is reconnected if it is enabled in the settings.
+func (c *Client) watchState(ctx context.Context, cn *connection, handler StateHandler) {
+	conn := cn.conn
+	state := conn.GetState()
+	for {
+		if state == connectivity.Shutdown {
+			return
+		}
+
+		if state == connectivity.Ready {
+			cn.setError(nil)
+		}
+		if handler != nil {
+			s := &entity.ConnectionState{State: state.String(), Time: time.Now()}
+			if err := cn.getError(); err != nil && state == connectivity.TransientFailure {
+				s.Error = err.Error()
+			}
+			handler(s)
+		}
+
+		if settings := c.settings(); state == connectivity.Idle && (settings == nil || settings.IsAutoReconnect()) {
+			conn.Connect()
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+		state = conn.GetState()
+	}
+}
+
+func isEnvProxy() bool {
+	for _, name := range []string{"HTTPS_PROXY", "https_proxy"} {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pkcs12" // nolint:staticcheck
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+// getTLSConfig returns the TLS config of the server, the certificate files are read on every call.
+func (c *Client) getTLSConfig(cn *connection, addr string) (*tls.Config, error) {
+	opts := c.options()
+
+	pool, err := c.getRootCAs()
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	cfg := &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: opts.insecureSkipVerify,
+		ServerName:         opts.serverName,
+		MinVersion:         entity.TLSVersions[opts.minTLSVersion],
+		MaxVersion:         entity.TLSVersions[opts.maxTLSVersion],
+		NextProtos:         opts.alpn,
+		VerifyConnection:   c.captureTLS(cn, c.getServerName(addr), opts.insecureSkipVerify),
+	}
+
+	for _, name := range opts.cipherSuites {
+		id, ok := entity.TLSCipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite: %s", name)
+		}
+		cfg.CipherSuites = append(cfg.CipherSuites, id)
+	}
+
+	cert, err := c.getClientCertificate()
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		cfg.Certificates = []tls.Certificate{*cert}
+	}
+
+	return cfg, nil
+}
+
+// getRootCAs returns the trusted root certificates, nil uses the system CA pool.
+func (c *Client) getRootCAs() (*x509.CertPool, error) {
+	opts := c.options()
+
+	rootPEM := []byte(opts.rootCertificate)
+	if opts.rootCertificatePath != "" {
+		data, err := os.ReadFile(opts.rootCertificatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read server CA's certificate: %v", err)
+		}
+		rootPEM = append(append(rootPEM, '\n'), data...)
+	}
+
+	if len(bytes.TrimSpace(rootPEM)) == 0 {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+	if opts.systemRoots {
+		var err error
+		if pool, err = x509.SystemCertPool(); err != nil {
+			return nil, fmt.Errorf("failed to load system CA pool: %v", err)
+		}
+	}
+	if !pool.AppendCertsFromPEM(rootPEM) {
+		return nil, errors.New("failed to add server CA's certificate")
+	}
+
+	return pool, nil
+}
+
+// getClientCertificate returns the client certificate from the PKCS#12 bundle, the files or the pasted PEM.
+func (c *Client) getClientCertificate() (*tls.Certificate, error) {
+	opts := c.options()
+
+	if opts.pkcs12Path != "" {
+		return loadPKCS12(opts.pkcs12Path, opts.pkcs12Password)
+	}
+
+	certPEM, keyPEM := []byte(opts.clientCertificate), []byte(opts.clientKey)
+	if opts.clientCertificatePath != "" {
+		data, err := os.ReadFile(opts.clientCertificatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %v", err)
+		}
+		certPEM = data
+	}
+	if opts.clientKeyPath != "" {
+		data, err := os.ReadFile(opts.clientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client private key: %v", err)
+		}
+		keyPEM = data
+	}
+
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// loadPKCS12 reads the client certificate, its chain and private key from the PKCS#12 bundle.
+func loadPKCS12(path, password string) (*tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PKCS#12 bundle: %v", err)
+	}
+
+	blocks, err := pkcs12.ToPEM(data, password)
+	if err != nil {
+		var notImplemented pkcs12.NotImplementedError
+		if errors.As(err, &notImplemented) {
+			return nil, fmt.Errorf("unsupported PKCS#12 bundle, export it with the legacy encryption (openssl pkcs12 -export -legacy): %v", err)
+		}
+		return nil, fmt.Errorf("failed to decode PKCS#12 bundle: %v", err)
+	}
+
+	var (
+		keyPEM []byte
+		certs  [][]byte
+	)
+	for _, b := range blocks {
+		b.Headers = nil
+		if b.Type == "CERTIFICATE" {
+			certs = append(certs, pem.EncodeToMemory(b))
+		} else {
+			keyPEM = pem.EncodeToMemory(b)
+		}
+	}
+	if keyPEM == nil || len(certs) == 0 {
+		return nil, errors.New("PKCS#12 bundle does not contain the certificate and private key")
+	}
+
+	// the bundle does not define the order of the certificates, the leaf is the one matching the key
+	for i := range certs {
+		chain := append([][]byte{certs[i]}, append(append([][]byte{}, certs[:i]...), certs[i+1:]...)...)
+		cert, err := tls.X509KeyPair(bytes.Join(chain, nil), keyPEM)
+		if err == nil {
+			return &cert, nil
+		}
+	}
+
+	return nil, errors.New("PKCS#12 bundle does not contain the certificate of the private key")
+}
+
+// GetTLSInfo returns the parameters of the last TLS handshake of the connection, nil if there was no handshake.
+func (c *Client) GetTLSInfo() *entity.TLSInfo {
+	c.connectionMux.RLock()
+	defer c.connectionMux.RUnlock()
+
+	if c.cur == nil {
+		return nil
+	}
+	return c.cur.getTLSInfo()
+}
+
+// captureTLS returns the callback storing the parameters of every handshake,
+// the hostname is checked only if the verification is disabled, otherwise the mismatch fails the handshake.
+func (c *Client) captureTLS(cn *connection, serverName string, insecure bool) func(cs tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		info := &entity.TLSInfo{
+			Version:      tls.VersionName(cs.Version),
+			CipherSuite:  tls.CipherSuiteName(cs.CipherSuite),
+			ALPN:         cs.NegotiatedProtocol,
+			ServerName:   serverName,
+			Certificates: make([]*entity.CertificateInfo, 0, len(cs.PeerCertificates)),
+		}
+
+		days := 0
+		if settings := c.settings(); settings != nil && settings.CertExpiryWarningDays != nil {
+			days = *settings.CertExpiryWarningDays
+		}
+
+		now := time.Now()
+		for _, cert := range cs.PeerCertificates {
+			ci := newCertificateInfo(cert)
+			if w := ci.ExpiryWarning(now, days); w != "" {
+				info.Warnings = append(info.Warnings, w)
+			}
+			info.Certificates = append(info.Certificates, ci)
+		}
+
+		if insecure && serverName != "" && len(cs.PeerCertificates) > 0 {
+			if err := cs.PeerCertificates[0].VerifyHostname(serverName); err != nil {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
+		}
+
+		cn.setTLSInfo(info)
+
+		return nil
+	}
+}
+
+// getServerName returns the name the server certificate is verified against:
+// the TLS server name override, the host of the authority or of the address.
+func (c *Client) getServerName(addr string) string {
+	opts := c.options()
+
+	if opts.serverName != "" {
+		return opts.serverName
+	}
+
+	host := opts.authority
+	if host == "" {
+		if _, ok := entity.UnixSocketPath(addr); ok {
+			return "localhost"
+		}
+		host = strings.TrimPrefix(strings.TrimPrefix(addr, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+
+	return host
+}
+
+func newCertificateInfo(cert *x509.Certificate) *entity.CertificateInfo {
+	fingerprint := sha256.Sum256(cert.Raw)
+	hexBytes := make([]string, len(fingerprint))
+	for i, b := range fingerprint {
+		hexBytes[i] = fmt.Sprintf("%02X", b)
+	}
+
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+
+	return &entity.CertificateInfo{
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		DNSNames:    cert.DNSNames,
+		IPAddresses: ips,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		Serial:      cert.SerialNumber.Text(16),
+		Fingerprint: strings.Join(hexBytes, ":"),
+		IsCA:        cert.IsCA,
+	}
+}
@@ -0,0 +1,489 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+// field numbers of google.api.http.
+const (
+	httpRuleExtension    protowire.Number = 72295728
+	httpRuleGet          protowire.Number = 2
+	httpRulePut          protowire.Number = 3
+	httpRulePost         protowire.Number = 4
+	httpRuleDelete       protowire.Number = 5
+	httpRulePatch        protowire.Number = 6
+	httpRuleBody         protowire.Number = 7
+	httpRuleCustom       protowire.Number = 8
+	httpRuleResponseBody protowire.Number = 12
+	httpCustomKind       protowire.Number = 1
+	httpCustomPath       protowire.Number = 2
+)
+
+// prefixes of the metadata headers of grpc-gateway.
+const (
+	gatewayMetadataPrefix = "grpc-metadata-"
+	gatewayTrailerPrefix  = "grpc-trailer-"
+)
+
+var httpRuleMethods = map[protowire.Number]string{
+	httpRuleGet:    http.MethodGet,
+	httpRulePut:    http.MethodPut,
+	httpRulePost:   http.MethodPost,
+	httpRuleDelete: http.MethodDelete,
+	httpRulePatch:  http.MethodPatch,
+}
+
+type gatewayError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+type gatewayStreamChunk struct {
+	Result json.RawMessage `json:"result"`
+	Error  *gatewayError   `json:"error"`
+}
+
+// getHTTPRule returns the google.api.http option of the method, the option is read from the wire format
+// because the annotations are usually not registered in the application.
+func getHTTPRule(md *desc.MethodDescriptor) *entity.HTTPRule {
+	opts := md.GetMethodOptions()
+	if opts == nil {
+		return nil
+	}
+
+	data, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+
+	var rule *entity.HTTPRule
+	rangeFields(data, func(num protowire.Number, v []byte) {
+		if num == httpRuleExtension {
+			rule = parseHTTPRule(v)
+		}
+	})
+
+	return rule
+}
+
+func parseHTTPRule(data []byte) *entity.HTTPRule {
+	rule := &entity.HTTPRule{}
+
+	rangeFields(data, func(num protowire.Number, v []byte) {
+		switch num {
+		case httpRuleGet, httpRulePut, httpRulePost, httpRuleDelete, httpRulePatch:
+			rule.Method, rule.Path = httpRuleMethods[num], string(v)
+		case httpRuleCustom:
+			rangeFields(v, func(num protowire.Number, v []byte) {
+				switch num {
+				case httpCustomKind:
+					rule.Method = string(v)
+				case httpCustomPath:
+					rule.Path = string(v)
+				}
+			})
+		case httpRuleBody:
+			rule.Body = string(v)
+		case httpRuleResponseBody:
+			rule.ResponseBody = string(v)
+		}
+	})
+
+	if rule.Method == "" || rule.Path == "" {
+		return nil
+	}
+
+	return rule
+}
+
+// rangeFields calls f for every length-delimited field of the message.
+func rangeFields(data []byte, f func(num protowire.Number, v []byte)) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return
+		}
+		data = data[n:]
+
+		if typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return
+			}
+			f(num, v)
+			data = data[n:]
+			continue
+		}
+
+		if n = protowire.ConsumeFieldValue(num, typ, data); n < 0 {
+			return
+		}
+		data = data[n:]
+	}
+}
+
+// newTranscodingStream calls the method through the HTTP/JSON gateway, the fields bound by the path template
+// are excluded from the body and the query string.
+func (w *webTransport) newTranscodingStream(ctx context.Context, method *entity.Method, ms *dynamic.Message) (*webStream, error) {
+	rule := method.HTTPRule
+	if rule == nil {
+		return nil, status.Errorf(codes.Unimplemented, "method %s has no google.api.http option", method.Name)
+	}
+
+	full, err := messageToJSONMap(ms, true)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := messageToJSONMap(ms, false)
+	if err != nil {
+		return nil, err
+	}
+
+	path, bound, err := expandPathTemplate(rule.Path, full)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, f := range bound {
+		deleteJSONField(fields, f)
+	}
+
+	var body io.Reader
+	switch rule.Body {
+	case "":
+	case "*":
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		fields = nil
+	default:
+		v, ok := fields[rule.Body]
+		if !ok {
+			v = full[rule.Body]
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		delete(fields, rule.Body)
+	}
+
+	query := url.Values{}
+	flattenQuery(query, "", fields)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rule.Method, w.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("TE", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", time.Until(deadline).Milliseconds()))
+	}
+
+	if err := w.setHeaders(ctx, req, gatewayMetadataPrefix); err != nil {
+		return nil, err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &webStream{
+		transport: w,
+		method:    method.Descriptor,
+		resp:      resp,
+		body:      resp.Body,
+		rule:      rule,
+		unary:     !method.Descriptor.IsServerStreaming(),
+	}
+	s.header, s.trailer = gatewayMetadata(resp.Header)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer s.close()
+		return nil, s.gatewayError()
+	}
+
+	if !s.unary {
+		s.decoder = json.NewDecoder(resp.Body)
+	}
+
+	return s, nil
+}
+
+// recvJSON returns the next response message of the HTTP/JSON gateway,
+// the server streaming response is the sequence of the JSON objects.
+func (s *webStream) recvJSON() (*dynamic.Message, error) {
+	if s.unary {
+		s.done = true
+		data, err := io.ReadAll(s.body)
+		if err != nil {
+			return nil, err
+		}
+		s.readTrailer()
+		return s.unmarshalJSON(data)
+	}
+
+	chunk := &gatewayStreamChunk{}
+	if err := s.decoder.Decode(chunk); errors.Is(err, io.EOF) {
+		s.done = true
+		s.readTrailer()
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	if chunk.Error != nil {
+		s.done = true
+		return nil, chunk.Error.status(s.resp.StatusCode)
+	}
+
+	return s.unmarshalJSON(chunk.Result)
+}
+
+func (s *webStream) unmarshalJSON(data []byte) (*dynamic.Message, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}")
+	}
+	if s.rule.ResponseBody != "" {
+		data = []byte(fmt.Sprintf("{%q:%s}", s.rule.ResponseBody, data))
+	}
+
+	ms := dynamic.NewMessage(s.method.GetOutputType())
+	if err := ms.UnmarshalJSONPB(&jsonpb.Unmarshaler{AllowUnknownFields: true}, data); err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// readTrailer reads the trailers sent as HTTP trailers after the body.
+func (s *webStream) readTrailer() {
+	_, trailer := gatewayMetadata(s.resp.Trailer)
+	for k, v := range trailer {
+		s.trailer.Append(k, v...)
+	}
+}
+
+func (s *webStream) gatewayError() error {
+	data, err := io.ReadAll(s.body)
+	if err == nil {
+		e := &gatewayError{}
+		if err := json.Unmarshal(data, e); err == nil && (e.Code != 0 || e.Message != "" || e.Error != "") {
+			return e.status(s.resp.StatusCode)
+		}
+	}
+
+	return status.Error(httpStatusToCode(s.resp.StatusCode), s.resp.Status)
+}
+
+func (e *gatewayError) status(httpStatus int) error {
+	code := codes.Code(e.Code)
+	if e.Code == 0 {
+		code = httpStatusToCode(httpStatus)
+	}
+
+	msg := e.Message
+	if msg == "" {
+		msg = e.Error
+	}
+
+	return status.Error(code, msg)
+}
+
+// gatewayMetadata converts the response headers, grpc-gateway sends the metadata and trailers as prefixed headers.
+func gatewayMetadata(h http.Header) (metadata.MD, metadata.MD) {
+	header := metadata.MD{}
+	trailer := metadata.MD{}
+
+	for k, values := range h {
+		k = strings.ToLower(k)
+		switch {
+		case strings.HasPrefix(k, gatewayTrailerPrefix):
+			trailer.Append(strings.TrimPrefix(k, gatewayTrailerPrefix), values...)
+		case strings.HasPrefix(k, gatewayMetadataPrefix):
+			header.Append(strings.TrimPrefix(k, gatewayMetadataPrefix), values...)
+		default:
+			header.Append(k, values...)
+		}
+	}
+
+	return header, trailer
+}
+
+func messageToJSONMap(ms *dynamic.Message, emitDefaults bool) (map[string]interface{}, error) {
+	buf, err := ms.MarshalJSONPB(&jsonpb.Marshaler{OrigName: true, EmitDefaults: emitDefaults})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// expandPathTemplate substitutes the variables of the path template, e.g. /v1/{name=shelves/*}/books/{book.id},
+// returns the path and the bound field paths.
+func expandPathTemplate(tpl string, data map[string]interface{}) (string, []string, error) {
+	var (
+		path  strings.Builder
+		bound []string
+	)
+
+	for {
+		start := strings.IndexByte(tpl, '{')
+		if start < 0 {
+			path.WriteString(tpl)
+			break
+		}
+		end := strings.IndexByte(tpl[start:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("wrong path template: %s", tpl)
+		}
+		end += start
+
+		path.WriteString(tpl[:start])
+		field, pattern, _ := strings.Cut(tpl[start+1:end], "=")
+		tpl = tpl[end+1:]
+
+		v, ok := getJSONField(data, field)
+		if !ok {
+			return "", nil, fmt.Errorf("field %s of the path is not set", field)
+		}
+		value, err := jsonScalarToString(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("field %s of the path: %w", field, err)
+		}
+		if value == "" {
+			return "", nil, fmt.Errorf("field %s of the path is empty", field)
+		}
+
+		if pattern == "" || pattern == "*" {
+			path.WriteString(url.PathEscape(value))
+		} else {
+			segments := strings.Split(value, "/")
+			for i, s := range segments {
+				segments[i] = url.PathEscape(s)
+			}
+			path.WriteString(strings.Join(segments, "/"))
+		}
+
+		bound = append(bound, field)
+	}
+
+	return path.String(), bound, nil
+}
+
+func getJSONField(data map[string]interface{}, path string) (interface{}, bool) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		v, ok := data[name]
+		if !ok {
+			return nil, false
+		}
+		if i == len(names)-1 {
+			return v, true
+		}
+		if data, ok = v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func deleteJSONField(data map[string]interface{}, path string) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		if i == len(names)-1 {
+			delete(data, name)
+			return
+		}
+		v, ok := data[name].(map[string]interface{})
+		if !ok {
+			return
+		}
+		data = v
+	}
+}
+
+func jsonScalarToString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number, bool:
+		return fmt.Sprint(t), nil
+	default:
+		return "", fmt.Errorf("value of type %T can not be a part of the path", v)
+	}
+}
+
+// flattenQuery adds the fields to the query string, the nested messages are joined by dots,
+// the repeated fields are repeated parameters.
+func flattenQuery(query url.Values, prefix string, data map[string]interface{}) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+
+		switch t := data[k].(type) {
+		case map[string]interface{}:
+			flattenQuery(query, name, t)
+		case []interface{}:
+			for _, item := range t {
+				if s, err := jsonScalarToString(item); err == nil {
+					query.Add(name, s)
+				}
+			}
+		case nil:
+		default:
+			if s, err := jsonScalarToString(t); err == nil {
+				query.Add(name, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,644 @@
+// Package grpc provides basic gRPC functions.
+package grpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	frameHeaderSize     = 5
+	frameFlagCompressed = 0x01
+	frameFlagEndStream  = 0x02
+	frameFlagTrailer    = 0x80
+)
+
+var connectCodes = map[string]codes.Code{
+	"canceled":            codes.Canceled,
+	"unknown":             codes.Unknown,
+	"invalid_argument":    codes.InvalidArgument,
+	"deadline_exceeded":   codes.DeadlineExceeded,
+	"not_found":           codes.NotFound,
+	"already_exists":      codes.AlreadyExists,
+	"permission_denied":   codes.PermissionDenied,
+	"resource_exhausted":  codes.ResourceExhausted,
+	"failed_precondition": codes.FailedPrecondition,
+	"aborted":             codes.Aborted,
+	"out_of_range":        codes.OutOfRange,
+	"unimplemented":       codes.Unimplemented,
+	"internal":            codes.Internal,
+	"unavailable":         codes.Unavailable,
+	"data_loss":           codes.DataLoss,
+	"unauthenticated":     codes.Unauthenticated,
+}
+
+// webTransport calls methods over the gRPC-Web, Connect and HTTP/JSON protocols.
+type webTransport struct {
+	protocol  string
+	baseURL   string
+	client    *http.Client
+	auth      authHeaders
+	authority string
+	userAgent string
+}
+
+// webStream response of the gRPC-Web or Connect call.
+type webStream struct {
+	transport *webTransport
+	method    *desc.MethodDescriptor
+	resp      *http.Response
+	body      io.Reader
+	header    metadata.MD
+	trailer   metadata.MD
+	rule      *entity.HTTPRule
+	decoder   *json.Decoder
+	unary     bool
+	done      bool
+}
+
+type connectError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type connectEndStream struct {
+	Error    *connectError       `json:"error"`
+	Metadata map[string][]string `json:"metadata"`
+}
+
+func (c *Client) newWebTransport(cn *connection, addr string, auth *entity.Auth) (*webTransport, error) {
+	opts := c.options()
+
+	var cfg *tls.Config
+	if !opts.noTLS {
+		var err error
+		if cfg, err = c.getTLSConfig(cn, addr); err != nil {
+			return nil, err
+		}
+	}
+
+	headers, err := getAuthHeaders(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := c.getDialer(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := addr
+	if path, ok := entity.UnixSocketPath(addr); ok {
+		baseURL = "localhost"
+		dialer = func(ctx context.Context, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+	}
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		if opts.noTLS {
+			baseURL = "http://" + baseURL
+		} else {
+			baseURL = "https://" + baseURL
+		}
+	}
+
+	return &webTransport{
+		protocol:  opts.transport,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		client:    &http.Client{Transport: newHTTPTransport(cfg, opts.http2, dialer)},
+		auth:      headers,
+		authority: opts.authority,
+		userAgent: opts.userAgent,
+	}, nil
+}
+
+// newHTTPTransport returns HTTP/1.1 or HTTP/2 transport, HTTP/2 without TLS uses the prior knowledge (h2c).
+// The connections are established by the dialer if it is set.
+func newHTTPTransport(cfg *tls.Config, useHTTP2 bool, dialer contextDialer) http.RoundTripper {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if dialer != nil {
+			return dialer(ctx, addr)
+		}
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+
+	if !useHTTP2 {
+		return &http.Transport{
+			DialContext:     dial,
+			TLSClientConfig: cfg,
+			TLSNextProto:    map[string]func(string, *tls.Conn) http.RoundTripper{},
+		}
+	}
+
+	t := &http2.Transport{TLSClientConfig: cfg}
+	if cfg == nil {
+		t.AllowHTTP = true
+		t.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dial(ctx, network, addr)
+		}
+	} else if dialer != nil {
+		t.DialTLSContext = func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+			conn, err := dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			tlsConn := tls.Client(conn, cfg)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+			return tlsConn, nil
+		}
+	}
+
+	return t
+}
+
+func (w *webTransport) close() {
+	w.client.CloseIdleConnections()
+}
+
+func (w *webTransport) isGRPCWeb() bool {
+	return w.protocol == entity.TransportGRPCWeb || w.protocol == entity.TransportGRPCWebText
+}
+
+func (w *webTransport) contentType(stream bool) string {
+	switch w.protocol {
+	case entity.TransportGRPCWeb:
+		return "application/grpc-web+proto"
+	case entity.TransportGRPCWebText:
+		return "application/grpc-web-text+proto"
+	case entity.TransportConnectJSON:
+		if stream {
+			return "application/connect+json"
+		}
+		return "application/json"
+	default:
+		if stream {
+			return "application/connect+proto"
+		}
+		return "application/proto"
+	}
+}
+
+func (w *webTransport) marshal(ms *dynamic.Message) ([]byte, error) {
+	if w.protocol == entity.TransportConnectJSON {
+		return ms.MarshalJSONPB(&jsonpb.Marshaler{})
+	}
+	return ms.Marshal()
+}
+
+func (w *webTransport) unmarshal(md *desc.MethodDescriptor, data []byte) (*dynamic.Message, error) {
+	ms := dynamic.NewMessage(md.GetOutputType())
+	if w.protocol == entity.TransportConnectJSON {
+		return ms, ms.UnmarshalJSONPB(&jsonpb.Unmarshaler{AllowUnknownFields: true}, data)
+	}
+	return ms, ms.Unmarshal(data)
+}
+
+// newStream sends the request message, Connect unary methods are called without the message envelope.
+func (w *webTransport) newStream(ctx context.Context, method *entity.Method, ms *dynamic.Message) (*webStream, error) {
+	if w.protocol == entity.TransportHTTPJSON {
+		return w.newTranscodingStream(ctx, method, ms)
+	}
+
+	md := method.Descriptor
+	unary := !md.IsServerStreaming() && !w.isGRPCWeb()
+
+	body, err := w.marshal(ms)
+	if err != nil {
+		return nil, err
+	}
+	if !unary {
+		body = envelope(0, body)
+	}
+	if w.protocol == entity.TransportGRPCWebText {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/%s/%s", w.baseURL, md.GetService().GetFullyQualifiedName(), md.GetName()), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := w.contentType(!unary)
+	req.Header.Set("Content-Type", contentType)
+
+	deadline, hasDeadline := ctx.Deadline()
+	if w.isGRPCWeb() {
+		req.Header.Set("Accept", contentType)
+		req.Header.Set("X-Grpc-Web", "1")
+		if hasDeadline {
+			req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", time.Until(deadline).Milliseconds()))
+		}
+	} else {
+		req.Header.Set("Connect-Protocol-Version", "1")
+		if hasDeadline {
+			req.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(time.Until(deadline).Milliseconds(), 10))
+		}
+	}
+
+	if err := w.setHeaders(ctx, req, ""); err != nil {
+		return nil, err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &webStream{
+		transport: w,
+		method:    md,
+		resp:      resp,
+		body:      resp.Body,
+		unary:     unary,
+	}
+	s.header, s.trailer = headerToMetadata(resp.Header, unary)
+
+	if resp.StatusCode != http.StatusOK {
+		defer s.close()
+		return nil, s.httpError()
+	}
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, strings.TrimSuffix(contentType, "+proto")) {
+		defer s.close()
+		return nil, status.Errorf(httpStatusToCode(resp.StatusCode), "unexpected content type %q", ct)
+	}
+
+	if w.protocol == entity.TransportGRPCWebText {
+		s.body = &base64Reader{r: bufio.NewReader(resp.Body)}
+	}
+
+	return s, nil
+}
+
+// setHeaders sets the host, user agent, authentication headers and the request metadata, the metadata keys are prefixed with the prefix.
+func (w *webTransport) setHeaders(ctx context.Context, req *http.Request, prefix string) error {
+	if w.authority != "" {
+		req.Host = w.authority
+	}
+	if w.userAgent != "" {
+		req.Header.Set("User-Agent", w.userAgent)
+	}
+
+	if w.auth != nil {
+		headers, err := w.auth(ctx)
+		if err != nil {
+			return err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	if outgoing, ok := metadata.FromOutgoingContext(ctx); ok {
+		for k, values := range outgoing {
+			for _, v := range values {
+				if strings.HasSuffix(k, "-bin") {
+					v = base64.StdEncoding.EncodeToString([]byte(v))
+				}
+				req.Header.Add(prefix+k, v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// recv returns the next response message, io.EOF is returned after the successful end of the call.
+func (s *webStream) recv() (*dynamic.Message, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	if s.transport.protocol == entity.TransportHTTPJSON {
+		return s.recvJSON()
+	}
+
+	if s.unary {
+		s.done = true
+		data, err := io.ReadAll(s.body)
+		if err != nil {
+			return nil, err
+		}
+		return s.transport.unmarshal(s.method, data)
+	}
+
+	for {
+		var header [frameHeaderSize]byte
+		if _, err := io.ReadFull(s.body, header[:]); errors.Is(err, io.EOF) {
+			s.done = true
+			return nil, s.endOfBody()
+		} else if err != nil {
+			return nil, err
+		}
+
+		flags := header[0]
+		data := make([]byte, binary.BigEndian.Uint32(header[1:]))
+		if _, err := io.ReadFull(s.body, data); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case flags&frameFlagCompressed != 0:
+			return nil, status.Error(codes.Internal, "compressed messages are not supported")
+		case s.transport.isGRPCWeb() && flags&frameFlagTrailer != 0:
+			s.done = true
+			s.trailer = parseTrailer(data)
+			return nil, grpcStatus(s.trailer)
+		case !s.transport.isGRPCWeb() && flags&frameFlagEndStream != 0:
+			s.done = true
+			return nil, s.endStream(data)
+		default:
+			return s.transport.unmarshal(s.method, data)
+		}
+	}
+}
+
+// endOfBody checks the status of the gRPC-Web trailers-only response.
+func (s *webStream) endOfBody() error {
+	if s.transport.isGRPCWeb() && s.header.Get("grpc-status") != nil {
+		return grpcStatus(s.header)
+	}
+	return status.Error(codes.Internal, "the server closed the stream without the status")
+}
+
+func (s *webStream) endStream(data []byte) error {
+	end := &connectEndStream{}
+	if err := json.Unmarshal(data, end); err != nil {
+		return status.Errorf(codes.Internal, "failed to parse the end of stream message: %v", err)
+	}
+
+	s.trailer = metadata.MD{}
+	for k, values := range end.Metadata {
+		s.trailer.Append(k, values...)
+	}
+
+	if end.Error != nil {
+		return end.Error.status()
+	}
+
+	return io.EOF
+}
+
+func (s *webStream) httpError() error {
+	if s.transport.isGRPCWeb() {
+		if s.header.Get("grpc-status") != nil {
+			return grpcStatus(s.header)
+		}
+	} else if data, err := io.ReadAll(s.body); err == nil {
+		e := &connectError{}
+		if err := json.Unmarshal(data, e); err == nil && e.Code != "" {
+			return e.status()
+		}
+	}
+
+	return status.Error(httpStatusToCode(s.resp.StatusCode), s.resp.Status)
+}
+
+func (s *webStream) close() {
+	_ = s.resp.Body.Close()
+}
+
+func (e *connectError) status() error {
+	code, ok := connectCodes[e.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	return status.Error(code, e.Message)
+}
+
+func (c *Client) webQuery(s *session, ms *dynamic.Message) error {
+	switch s.method.Type {
+	case entity.MethodTypeUnary:
+		go c.webUnary(s, ms)
+	case entity.MethodTypeServerStream:
+		c.webServerStream(s, ms)
+	default:
+		err := fmt.Errorf("client streaming is not supported by the %s transport", c.options().transport)
+		c.responseError(s, err, "")
+		c.endSession(s)
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) webUnary(s *session, ms *dynamic.Message) {
+	defer c.endSession(s)
+
+	stream, err := s.web.newStream(s.ctx, s.method, ms)
+	if err != nil {
+		c.response(s, nil, nil, nil, c.webError(s, err))
+		return
+	}
+	defer stream.close()
+
+	resp, err := stream.recv()
+	switch {
+	case errors.Is(err, io.EOF):
+		err = status.Error(codes.Internal, "the server returned no response message")
+	case err == nil:
+		if _, err = stream.recv(); errors.Is(err, io.EOF) {
+			err = nil
+		} else if err == nil {
+			err = status.Error(codes.Internal, "the server returned several response messages")
+		}
+	}
+
+	c.response(s, resp, stream.header, stream.trailer, c.webError(s, err))
+}
+
+func (c *Client) webServerStream(s *session, ms *dynamic.Message) {
+	stream, err := s.web.newStream(s.ctx, s.method, ms)
+	if err != nil {
+		c.responseError(s, c.webError(s, err), "")
+		c.endSession(s)
+		return
+	}
+
+	go func() {
+		defer func() {
+			stream.close()
+			c.endSession(s)
+		}()
+
+		for {
+			data, err := stream.recv()
+			if errors.Is(err, io.EOF) {
+				c.response(s, nil, stream.header, stream.trailer, nil)
+				return
+			} else if err != nil {
+				c.responseError(s, c.webError(s, err), time.Since(s.startTime).String())
+				return
+			}
+			c.response(s, data, stream.header, stream.trailer, nil)
+		}
+	}()
+}
+
+// webError converts the transport error to the gRPC status error.
+func (c *Client) webError(s *session, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := s.ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+func envelope(flags byte, data []byte) []byte {
+	buf := make([]byte, frameHeaderSize+len(data))
+	buf[0] = flags
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(data)))
+	copy(buf[frameHeaderSize:], data)
+	return buf
+}
+
+// headerToMetadata converts the response headers, the Connect unary trailers are sent as prefixed headers.
+func headerToMetadata(h http.Header, unary bool) (metadata.MD, metadata.MD) {
+	header := metadata.MD{}
+	trailer := metadata.MD{}
+
+	for k, values := range h {
+		k = strings.ToLower(k)
+		if unary && strings.HasPrefix(k, "trailer-") {
+			trailer.Append(strings.TrimPrefix(k, "trailer-"), values...)
+			continue
+		}
+		header.Append(k, values...)
+	}
+
+	return header, trailer
+}
+
+func parseTrailer(data []byte) metadata.MD {
+	md := metadata.MD{}
+	for _, line := range strings.Split(string(data), "\r\n") {
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		md.Append(strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v))
+	}
+	return md
+}
+
+// grpcStatus returns the status error of the gRPC-Web call, the status keys are removed from the metadata.
+func grpcStatus(md metadata.MD) error {
+	defer func() {
+		delete(md, "grpc-status")
+		delete(md, "grpc-message")
+	}()
+
+	var code, msg string
+	if v := md.Get("grpc-status"); len(v) > 0 {
+		code = v[0]
+	}
+	if v := md.Get("grpc-message"); len(v) > 0 {
+		msg = v[0]
+		if unescaped, err := url.PathUnescape(msg); err == nil {
+			msg = unescaped
+		}
+	}
+
+	n, err := strconv.ParseUint(code, 10, 32)
+	if err != nil {
+		return status.Errorf(codes.Internal, "malformed grpc-status: %q", code)
+	}
+	if codes.Code(n) == codes.OK {
+		return io.EOF
+	}
+
+	return status.Error(codes.Code(n), msg)
+}
+
+// httpStatusToCode maps HTTP status to the gRPC code as described in the gRPC HTTP/2 protocol.
+func httpStatusToCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+// base64Reader decodes the gRPC-Web text response, every chunk of the response may be padded.
+type base64Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func (b *base64Reader) Read(p []byte) (int, error) {
+	for len(b.buf) == 0 {
+		var (
+			quantum [4]byte
+			n       int
+		)
+		for n < len(quantum) {
+			ch, err := b.r.ReadByte()
+			if errors.Is(err, io.EOF) && n > 0 {
+				return 0, io.ErrUnexpectedEOF
+			} else if err != nil {
+				return 0, err
+			}
+			if ch == '\r' || ch == '\n' {
+				continue
+			}
+			quantum[n] = ch
+			n++
+		}
+
+		data := make([]byte, 3)
+		size, err := base64.StdEncoding.Decode(data, quantum[:])
+		if err != nil {
+			return 0, err
+		}
+		b.buf = data[:size]
+	}
+
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+
+	return n, nil
+}
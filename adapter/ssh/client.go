@@ -0,0 +1,160 @@
+// Package ssh provides SSH tunnels to the gRPC servers.
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/logger"
+)
+
+// Client object capable of interacting with Client.
+type Client struct {
+	ctx context.Context
+	cfg *entity.Settings
+	log *logger.Zerolog
+}
+
+// New creates a new Client.
+func New(ctx context.Context, log *logger.Zerolog) *Client {
+	return &Client{
+		ctx: ctx,
+		log: log,
+	}
+}
+
+// SetSettings sets application settings.
+func (c *Client) SetSettings(cfg *entity.Settings) {
+	c.cfg = cfg
+}
+
+// Tunnel connects to the jump host and forwards the local port to the remote address.
+func (c *Client) Tunnel(t *entity.SSHTunnel) (entity.SSHTunnelControl, error) {
+	config, closeAgent, err := c.clientConfig(t)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	client, err := ssh.Dial("tcp", t.HostAddr(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", t.LocalPort))
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	ctrl := &TunnelControl{
+		client:     client,
+		listener:   listener,
+		remoteAddr: t.RemoteAddr,
+		errHandler: t.ErrHandler,
+		log:        c.log,
+		stopCh:     make(chan struct{}),
+	}
+
+	go ctrl.accept()
+	go ctrl.wait()
+	go ctrl.keepAlive()
+
+	return ctrl, nil
+}
+
+func (c *Client) clientConfig(t *entity.SSHTunnel) (*ssh.ClientConfig, func(), error) {
+	hostKeyCallback, err := getHostKeyCallback(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeAgent := func() {}
+	auth := make([]ssh.AuthMethod, 0, 2)
+
+	if t.PrivateKeyFile != "" {
+		signer, err := loadPrivateKey(t.PrivateKeyFile, t.Passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if t.UseAgent {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if sock == "" {
+			return nil, nil, errors.New("SSH agent is not running, SSH_AUTH_SOCK is not set")
+		}
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to SSH agent: %v", err)
+		}
+		closeAgent = func() {
+			if err := conn.Close(); err != nil {
+				c.log.Error().Msgf("failed to close SSH agent connection: %v", err)
+			}
+		}
+		auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	config := &ssh.ClientConfig{
+		User:            t.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}
+	if c.cfg != nil && c.cfg.ConnectTimeout != nil && *c.cfg.ConnectTimeout > 0 {
+		config.Timeout = time.Duration(*c.cfg.ConnectTimeout) * time.Second
+	}
+
+	return config, closeAgent, nil
+}
+
+func loadPrivateKey(file, passphrase string) (ssh.Signer, error) {
+	key, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if passphrase != "" {
+		return ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		var missingErr *ssh.PassphraseMissingError
+		if errors.As(err, &missingErr) {
+			return nil, errors.New("the private key is protected by a passphrase")
+		}
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+func getHostKeyCallback(t *entity.SSHTunnel) (ssh.HostKeyCallback, error) {
+	if t.InsecureIgnoreHostKey {
+		// nolint:gosec
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	file := t.KnownHostsFile
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	return knownhosts.New(file)
+}
@@ -0,0 +1,117 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/forest33/warthog/pkg/logger"
+)
+
+const keepAliveInterval = 30 * time.Second
+
+// TunnelControl SSH tunnel control.
+type TunnelControl struct {
+	client     *ssh.Client
+	listener   net.Listener
+	remoteAddr string
+	errHandler func(err error)
+	log        *logger.Zerolog
+	stopCh     chan struct{}
+	closeOnce  sync.Once
+}
+
+// Close closes the local listener and the SSH connection.
+func (c *TunnelControl) Close() {
+	c.close()
+}
+
+// close closes the tunnel and returns true if it was open.
+func (c *TunnelControl) close() bool {
+	closed := false
+	c.closeOnce.Do(func() {
+		closed = true
+		close(c.stopCh)
+		if err := c.listener.Close(); err != nil {
+			c.log.Error().Msgf("failed to close SSH tunnel listener: %v", err)
+		}
+		if err := c.client.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			c.log.Error().Msgf("failed to close SSH connection: %v", err)
+		}
+	})
+	return closed
+}
+
+// fail closes the tunnel and reports the error unless the tunnel is closed already.
+func (c *TunnelControl) fail(err error) {
+	if c.close() && c.errHandler != nil {
+		c.errHandler(err)
+	}
+}
+
+func (c *TunnelControl) accept() {
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		go c.forward(conn)
+	}
+}
+
+func (c *TunnelControl) forward(local net.Conn) {
+	remote, err := c.client.Dial("tcp", c.remoteAddr)
+	if err != nil {
+		c.log.Error().Msgf("failed to connect to %s through the SSH tunnel: %v", c.remoteAddr, err)
+		_ = local.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+
+	go pipe(remote, local)
+	go pipe(local, remote)
+
+	select {
+	case <-done:
+	case <-c.stopCh:
+	}
+
+	_ = local.Close()
+	_ = remote.Close()
+}
+
+func (c *TunnelControl) wait() {
+	if err := c.client.Wait(); err != nil && !errors.Is(err, io.EOF) {
+		c.fail(fmt.Errorf("SSH connection closed: %v", err))
+		return
+	}
+	c.fail(errors.New("SSH connection closed"))
+}
+
+func (c *TunnelControl) keepAlive() {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			if _, _, err := c.client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				c.fail(err)
+				return
+			}
+		}
+	}
+}
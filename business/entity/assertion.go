@@ -0,0 +1,370 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// assertion subjects.
+const (
+	AssertSourceStatus  = "status"
+	AssertSourceLatency = "latency"
+	AssertSourceHeader  = "header"
+	AssertSourceTrailer = "trailer"
+	AssertSourceBody    = "body"
+)
+
+// assertion operators.
+const (
+	AssertOpEqual        = "=="
+	AssertOpNotEqual     = "!="
+	AssertOpLess         = "<"
+	AssertOpLessEqual    = "<="
+	AssertOpGreater      = ">"
+	AssertOpGreaterEqual = ">="
+	AssertOpExists       = "exists"
+	AssertOpNotExists    = "!exists"
+	AssertOpContains     = "contains"
+	AssertOpMatches      = "matches"
+)
+
+// Assertion check of the query response, e.g. `status == OK`, `trailer x-foo exists`,
+// `response.user.id != ""` or `latency < 200ms`.
+type Assertion struct {
+	Expression string `json:"expression"`
+	Source     string `json:"source"`
+	Path       string `json:"path,omitempty"`
+	Operator   string `json:"operator"`
+	Value      string `json:"value,omitempty"`
+}
+
+// AssertionResult result of the assertion check.
+type AssertionResult struct {
+	Expression string `json:"expression"`
+	Source     string `json:"source"`
+	Passed     bool   `json:"passed"`
+	Actual     string `json:"actual,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// AssertionsModel creates assertions from UI request.
+func AssertionsModel(v interface{}) ([]*Assertion, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.New("assertions not a []interface{}")
+	}
+
+	assertions := make([]*Assertion, 0, len(items))
+	for _, item := range items {
+		var (
+			a   *Assertion
+			err error
+		)
+		switch t := item.(type) {
+		case string:
+			a, err = ParseAssertion(t)
+		case map[string]interface{}:
+			a = &Assertion{}
+			err = a.Model(t)
+		default:
+			err = errors.New("assertion not a string or map[string]interface{}")
+		}
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+
+	return assertions, nil
+}
+
+// Model creates Assertion from UI request.
+func (a *Assertion) Model(req map[string]interface{}) error {
+	v, ok := req["expression"]
+	if !ok || v == nil {
+		return errors.New("assertion expression is empty")
+	}
+
+	expr, ok := v.(string)
+	if !ok {
+		return errors.New("expression not a string")
+	}
+
+	parsed, err := ParseAssertion(expr)
+	if err != nil {
+		return err
+	}
+	*a = *parsed
+
+	return nil
+}
+
+// ParseAssertion parses the assertion expression "<subject> <operator> [value]".
+func ParseAssertion(expr string) (*Assertion, error) {
+	a := &Assertion{Expression: strings.TrimSpace(expr)}
+
+	rest := a.Expression
+	subject := nextToken(&rest)
+	switch strings.ToLower(subject) {
+	case "":
+		return nil, errors.New("assertion expression is empty")
+	case "status", "code":
+		a.Source = AssertSourceStatus
+	case "latency", "time":
+		a.Source = AssertSourceLatency
+	case "header", "trailer":
+		a.Source = strings.ToLower(subject)
+		if a.Path = strings.ToLower(nextToken(&rest)); a.Path == "" {
+			return nil, fmt.Errorf("%s name is empty in assertion \"%s\"", a.Source, expr)
+		}
+	default:
+		a.Source = AssertSourceBody
+		a.Path = subject
+		for _, prefix := range []string{"response", "body"} {
+			if subject == prefix || strings.HasPrefix(subject, prefix+".") || strings.HasPrefix(subject, prefix+"[") {
+				a.Path = "$" + strings.TrimPrefix(subject, prefix)
+			}
+		}
+	}
+
+	a.Operator = strings.ToLower(nextToken(&rest))
+	if a.Operator == "not" || a.Operator == "!" {
+		a.Operator = "!" + strings.ToLower(nextToken(&rest))
+	}
+
+	switch a.Operator {
+	case AssertOpExists, AssertOpNotExists:
+		if rest != "" {
+			return nil, fmt.Errorf("unexpected value in assertion \"%s\"", expr)
+		}
+	case AssertOpEqual, AssertOpNotEqual, AssertOpLess, AssertOpLessEqual, AssertOpGreater, AssertOpGreaterEqual, AssertOpContains:
+		a.Value = unquote(rest)
+	case AssertOpMatches:
+		a.Value = unquote(rest)
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return nil, fmt.Errorf("wrong regular expression in assertion \"%s\": %v", expr, err)
+		}
+	case "=":
+		a.Operator = AssertOpEqual
+		a.Value = unquote(rest)
+	case "":
+		return nil, fmt.Errorf("operator is empty in assertion \"%s\"", expr)
+	default:
+		return nil, fmt.Errorf("unknown operator \"%s\" in assertion \"%s\"", a.Operator, expr)
+	}
+
+	if a.Source == AssertSourceLatency {
+		if _, err := parseLatency(a.Value); err != nil {
+			return nil, fmt.Errorf("wrong latency in assertion \"%s\": %v", expr, err)
+		}
+	}
+
+	return a, nil
+}
+
+// Evaluate checks the assertion against the query response.
+func (a *Assertion) Evaluate(resp *QueryResponse) *AssertionResult {
+	res := &AssertionResult{Expression: a.Expression, Source: a.Source}
+
+	var err error
+	switch a.Source {
+	case AssertSourceStatus:
+		res.Passed, res.Actual, err = a.evaluateStatus(resp)
+	case AssertSourceLatency:
+		res.Passed, res.Actual, err = a.evaluateLatency(resp)
+	case AssertSourceHeader, AssertSourceTrailer:
+		md := resp.Header
+		if a.Source == AssertSourceTrailer {
+			md = resp.Trailer
+		}
+		v, ok := md[a.Path]
+		res.Actual = strings.Join(v, ", ")
+		res.Passed, err = a.compare(res.Actual, ok)
+	case AssertSourceBody:
+		actual, errPath := GetValueByPath(resp.Message, a.Path)
+		exists := errPath == nil && resp.Message != nil
+		if exists {
+			if res.Actual, err = valueToString(actual); err != nil {
+				break
+			}
+		}
+		res.Passed, err = a.compare(res.Actual, exists)
+	default:
+		err = fmt.Errorf("unknown assertion source: %s", a.Source)
+	}
+
+	if err != nil {
+		res.Passed = false
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// String returns the assertion result description.
+func (r *AssertionResult) String() string {
+	switch {
+	case r.Error != "":
+		return fmt.Sprintf("%s: %s", r.Expression, r.Error)
+	case r.Passed:
+		return r.Expression
+	}
+	return fmt.Sprintf("%s: actual %q", r.Expression, r.Actual)
+}
+
+// AssertionsPassed returns true if all assertions of the response passed.
+func (r *QueryResponse) AssertionsPassed() bool {
+	for _, a := range r.Assertions {
+		if !a.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// IsLast returns true if the response is the last one of the call.
+func (r *QueryResponse) IsLast(methodType string) bool {
+	if r.Error != nil {
+		return true
+	}
+	switch methodType {
+	case MethodTypeUnary, MethodTypeClientStream:
+		return true
+	}
+	return r.JsonString == ""
+}
+
+func (a *Assertion) evaluateStatus(resp *QueryResponse) (bool, string, error) {
+	code := codes.OK
+	if resp.Error != nil {
+		code = codes.Code(resp.Error.Code)
+	}
+
+	if v, err := strconv.ParseUint(a.Value, 10, 32); err == nil {
+		passed, err := compareNumbers(a.Operator, float64(code), float64(v))
+		return passed, code.String(), err
+	}
+
+	normalize := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+	}
+
+	switch a.Operator {
+	case AssertOpEqual:
+		return normalize(code.String()) == normalize(a.Value), code.String(), nil
+	case AssertOpNotEqual:
+		return normalize(code.String()) != normalize(a.Value), code.String(), nil
+	}
+
+	return false, code.String(), fmt.Errorf("operator %s is not supported for the status name", a.Operator)
+}
+
+func (a *Assertion) evaluateLatency(resp *QueryResponse) (bool, string, error) {
+	actual, err := time.ParseDuration(resp.SpentTime)
+	if err != nil {
+		return false, resp.SpentTime, fmt.Errorf("unknown latency: %v", err)
+	}
+
+	expected, err := parseLatency(a.Value)
+	if err != nil {
+		return false, resp.SpentTime, err
+	}
+
+	passed, err := compareNumbers(a.Operator, float64(actual), float64(expected))
+	return passed, resp.SpentTime, err
+}
+
+func (a *Assertion) compare(actual string, exists bool) (bool, error) {
+	switch a.Operator {
+	case AssertOpExists:
+		return exists, nil
+	case AssertOpNotExists:
+		return !exists, nil
+	}
+
+	if !exists {
+		return false, errors.New("value not found")
+	}
+
+	switch a.Operator {
+	case AssertOpContains:
+		return strings.Contains(actual, a.Value), nil
+	case AssertOpMatches:
+		return regexp.MatchString(a.Value, actual)
+	}
+
+	x, errX := strconv.ParseFloat(actual, 64)
+	y, errY := strconv.ParseFloat(a.Value, 64)
+	if errX == nil && errY == nil {
+		return compareNumbers(a.Operator, x, y)
+	}
+
+	switch a.Operator {
+	case AssertOpEqual:
+		return actual == a.Value, nil
+	case AssertOpNotEqual:
+		return actual != a.Value, nil
+	case AssertOpLess:
+		return actual < a.Value, nil
+	case AssertOpLessEqual:
+		return actual <= a.Value, nil
+	case AssertOpGreater:
+		return actual > a.Value, nil
+	case AssertOpGreaterEqual:
+		return actual >= a.Value, nil
+	}
+
+	return false, fmt.Errorf("unknown operator: %s", a.Operator)
+}
+
+func compareNumbers(op string, x, y float64) (bool, error) {
+	switch op {
+	case AssertOpEqual:
+		return x == y, nil
+	case AssertOpNotEqual:
+		return x != y, nil
+	case AssertOpLess:
+		return x < y, nil
+	case AssertOpLessEqual:
+		return x <= y, nil
+	case AssertOpGreater:
+		return x > y, nil
+	case AssertOpGreaterEqual:
+		return x >= y, nil
+	}
+	return false, fmt.Errorf("operator %s is not supported for numbers", op)
+}
+
+// parseLatency parses the duration, a number without units is taken as milliseconds.
+func parseLatency(s string) (time.Duration, error) {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(v * float64(time.Millisecond)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+func nextToken(s *string) string {
+	str := strings.TrimSpace(*s)
+	end := strings.IndexAny(str, " \t")
+	if end < 0 {
+		*s = ""
+		return str
+	}
+	*s = strings.TrimSpace(str[end:])
+	return str[:end]
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// CompressionNone disables compression of the requests.
+	CompressionNone = "identity"
+	// CompressionGzip gzip compression of the requests.
+	CompressionGzip = "gzip"
+)
+
+// CallOptions gRPC call and connection options, the message sizes are in megabytes and the keepalive intervals in seconds.
+// The zero values of the server options use the settings, the zero values of the settings use the gRPC defaults.
+type CallOptions struct {
+	Compression                  string `json:"compression,omitempty"`
+	MaxRecvMsgSize               int    `json:"max_recv_msg_size,omitempty"`
+	MaxSendMsgSize               int    `json:"max_send_msg_size,omitempty"`
+	KeepaliveTime                int    `json:"keepalive_time,omitempty"`
+	KeepaliveTimeout             int    `json:"keepalive_timeout,omitempty"`
+	KeepalivePermitWithoutStream *bool  `json:"keepalive_permit_without_stream,omitempty"`
+	WaitForReady                 *bool  `json:"wait_for_ready,omitempty"`
+}
+
+// Model creates CallOptions from UI request.
+func (o *CallOptions) Model(opts map[string]interface{}) error {
+	if opts == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := opts["compression"]; ok && v != nil {
+		if o.Compression, ok = v.(string); !ok {
+			return errors.New("compression not a string")
+		}
+	}
+
+	for _, f := range []struct {
+		name  string
+		title string
+		value *int
+	}{
+		{"max_recv_msg_size", "max receive message size", &o.MaxRecvMsgSize},
+		{"max_send_msg_size", "max send message size", &o.MaxSendMsgSize},
+		{"keepalive_time", "keepalive time", &o.KeepaliveTime},
+		{"keepalive_timeout", "keepalive timeout", &o.KeepaliveTimeout},
+	} {
+		v, ok := opts[f.name]
+		if !ok || v == nil {
+			continue
+		}
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s not a float", f.title)
+		}
+		if n < 0 {
+			return fmt.Errorf("negative %s", f.title)
+		}
+		*f.value = int(n)
+	}
+
+	if v, ok := opts["keepalive_permit_without_stream"]; ok && v != nil {
+		b, ok := v.(bool)
+		if !ok {
+			return errors.New("keepalive permit without stream not a bool")
+		}
+		o.KeepalivePermitWithoutStream = &b
+	}
+	if v, ok := opts["wait_for_ready"]; ok && v != nil {
+		b, ok := v.(bool)
+		if !ok {
+			return errors.New("wait for ready not a bool")
+		}
+		o.WaitForReady = &b
+	}
+
+	return nil
+}
+
+// Merge returns the options with the zero values replaced by the default options.
+func (o *CallOptions) Merge(def *CallOptions) *CallOptions {
+	if o == nil && def == nil {
+		return &CallOptions{}
+	} else if o == nil {
+		return def.Merge(nil)
+	}
+
+	opts := *o
+	if def == nil {
+		return &opts
+	}
+
+	if opts.Compression == "" {
+		opts.Compression = def.Compression
+	}
+	if opts.MaxRecvMsgSize == 0 {
+		opts.MaxRecvMsgSize = def.MaxRecvMsgSize
+	}
+	if opts.MaxSendMsgSize == 0 {
+		opts.MaxSendMsgSize = def.MaxSendMsgSize
+	}
+	if opts.KeepaliveTime == 0 {
+		opts.KeepaliveTime = def.KeepaliveTime
+	}
+	if opts.KeepaliveTimeout == 0 {
+		opts.KeepaliveTimeout = def.KeepaliveTimeout
+	}
+	if opts.KeepalivePermitWithoutStream == nil {
+		opts.KeepalivePermitWithoutStream = def.KeepalivePermitWithoutStream
+	}
+	if opts.WaitForReady == nil {
+		opts.WaitForReady = def.WaitForReady
+	}
+
+	return &opts
+}
+
+// IsKeepaliveEnabled checks whether it is enabled client keepalive pings.
+func (o *CallOptions) IsKeepaliveEnabled() bool {
+	return o != nil && o.KeepaliveTime > 0
+}
+
+// IsWaitForReady checks whether the calls wait until the connection is ready instead of failing fast.
+func (o *CallOptions) IsWaitForReady() bool {
+	return o != nil && o.WaitForReady != nil && *o.WaitForReady
+}
+
+// validateServiceConfig checks that the service config is a JSON object, the policies are checked by gRPC on connect.
+func validateServiceConfig(cfg string) error {
+	if cfg == "" {
+		return nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(cfg), &obj); err != nil {
+		return fmt.Errorf("service config is not a JSON object: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// file names of the generated development certificates.
+const (
+	CAKeyFile             = "ca.key"
+	ServerCertificateFile = "server.pem"
+	ServerKeyFile         = "server.key"
+)
+
+// certificate key types.
+const (
+	CertificateKeyTypeRSA   = "rsa"
+	CertificateKeyTypeECDSA = "ecdsa"
+)
+
+// CertificatesRequest generate development CA and certificates request.
+type CertificatesRequest struct {
+	Dir          string   `json:"dir"`
+	KeyType      string   `json:"key_type"`
+	Hosts        []string `json:"hosts"`
+	ValidityDays int      `json:"validity_days"`
+	ServerID     int64    `json:"server_id"`
+}
+
+// CertificatesResponse generated development CA and certificates.
+type CertificatesResponse struct {
+	Files             map[string]string `json:"files"`
+	RootCertificate   string            `json:"root_certificate"`
+	ClientCertificate string            `json:"client_certificate"`
+	ClientKey         string            `json:"client_key"`
+	Server            *Workspace        `json:"server,omitempty"`
+}
+
+// Model creates CertificatesRequest from UI request.
+func (r *CertificatesRequest) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["dir"]; ok && v != nil {
+		if r.Dir, ok = v.(string); !ok {
+			return errors.New("directory not a string")
+		}
+	}
+	if v, ok := req["key_type"]; ok && v != nil {
+		if r.KeyType, ok = v.(string); !ok {
+			return errors.New("key type not a string")
+		}
+	}
+	if v, ok := req["hosts"]; ok && v != nil {
+		hosts, ok := v.([]interface{})
+		if !ok {
+			return errors.New("hosts not an array")
+		}
+		for _, h := range hosts {
+			host, ok := h.(string)
+			if !ok {
+				return errors.New("host not a string")
+			}
+			if host = strings.TrimSpace(host); host != "" {
+				r.Hosts = append(r.Hosts, host)
+			}
+		}
+	}
+	if v, ok := req["validity_days"]; ok && v != nil {
+		f, ok := v.(float64)
+		if !ok {
+			return errors.New("validity days not a float")
+		}
+		r.ValidityDays = int(f)
+	}
+	if v, ok := req["server_id"]; ok && v != nil {
+		f, ok := v.(float64)
+		if !ok {
+			return errors.New("server id not a float")
+		}
+		r.ServerID = int64(f)
+	}
+
+	if r.Dir == "" {
+		return errors.New("empty directory")
+	}
+	if r.KeyType != CertificateKeyTypeRSA && r.KeyType != CertificateKeyTypeECDSA {
+		return fmt.Errorf("unknown key type: %s", r.KeyType)
+	}
+	if len(r.Hosts) == 0 {
+		return errors.New("no server hosts")
+	}
+	if r.ValidityDays <= 0 {
+		return errors.New("validity must be at least one day")
+	}
+
+	return nil
+}
+
+// SetCertificates sets the root certificate and the client certificate of the server,
+// the client certificate files are cleared to take the pasted certificate.
+func (s *WorkspaceItemServer) SetCertificates(rootCertificate, clientCertificate, clientKey string) {
+	s.NoTLS = false
+	s.RootCertificate = rootCertificate
+	s.ClientCertificate = clientCertificate
+	s.ClientKey = clientKey
+	s.ClientCertificatePath = ""
+	s.ClientKeyPath = ""
+	s.PKCS12Path = ""
+	s.PKCS12Password = ""
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Connectivity states of the server connection.
+const (
+	ConnectionStateIdle             = "IDLE"
+	ConnectionStateConnecting       = "CONNECTING"
+	ConnectionStateReady            = "READY"
+	ConnectionStateTransientFailure = "TRANSIENT_FAILURE"
+)
+
+// ConnectionState connectivity state of the server connection.
+type ConnectionState struct {
+	ServerID int64     `json:"server_id"`
+	State    string    `json:"state"`
+	Error    string    `json:"error,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// String returns the state with the reason of the last failed attempt.
+func (s *ConnectionState) String() string {
+	if s.Error != "" {
+		return fmt.Sprintf("%s: %s", s.State, s.Error)
+	}
+	return s.State
+}
@@ -0,0 +1,163 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEnvironmentNotExists error environment not exists.
+	ErrEnvironmentNotExists = errors.New("environment not exists")
+
+	variableRegexp = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+)
+
+// Environment named set of variables.
+type Environment struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// EnvironmentRequest create/update/delete environment request.
+type EnvironmentRequest struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+// Model creates EnvironmentRequest from UI request.
+func (r *EnvironmentRequest) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["id"]; ok && v != nil {
+		if id, ok := v.(float64); !ok {
+			return errors.New("id not a float")
+		} else {
+			r.ID = int64(id)
+		}
+	}
+	if v, ok := req["name"]; ok && v != nil {
+		if r.Name, ok = v.(string); !ok {
+			return errors.New("name not a string")
+		}
+		r.Name = strings.TrimSpace(r.Name)
+	}
+	if v, ok := req["variables"]; ok && v != nil {
+		vars, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.New("variables not a map[string]interface{}")
+		}
+		r.Variables = make(map[string]string, len(vars))
+		for k, v := range vars {
+			if r.Variables[k], ok = v.(string); !ok {
+				return errors.New("variable value not a string")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Resolve replaces {{name}} references with the variable values, unknown references are left as is.
+func (e *Environment) Resolve(s string) string {
+	if e == nil || len(e.Variables) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	return variableRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		if v, ok := e.Variables[variableRegexp.FindStringSubmatch(ref)[1]]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// ResolveData replaces variable references in the string leaves of the request data.
+func (e *Environment) ResolveData(data map[string]interface{}) map[string]interface{} {
+	if e == nil || data == nil {
+		return data
+	}
+
+	return e.resolveValue(data).(map[string]interface{})
+}
+
+// ResolveMetadata replaces variable references in the metadata values.
+func (e *Environment) ResolveMetadata(metadata []string) []string {
+	if e == nil || metadata == nil {
+		return metadata
+	}
+
+	resolved := make([]string, len(metadata))
+	for i := range metadata {
+		if i%2 == 0 {
+			resolved[i] = metadata[i]
+		} else {
+			resolved[i] = e.Resolve(metadata[i])
+		}
+	}
+
+	return resolved
+}
+
+// ResolveServer returns a copy of the server with variable references resolved in the address, authentication and proxy.
+func (e *Environment) ResolveServer(s *WorkspaceItemServer) *WorkspaceItemServer {
+	if e == nil || s == nil {
+		return s
+	}
+
+	resolved := *s
+	resolved.Addr = e.Resolve(s.Addr)
+
+	if s.Auth != nil {
+		auth := *s.Auth
+		auth.Login = e.Resolve(auth.Login)
+		auth.Password = e.Resolve(auth.Password)
+		auth.Token = e.Resolve(auth.Token)
+		auth.Secret = e.Resolve(auth.Secret)
+		auth.PrivateKey = e.Resolve(auth.PrivateKey)
+		auth.HeaderPrefix = e.Resolve(auth.HeaderPrefix)
+		auth.GoogleToken = e.Resolve(auth.GoogleToken)
+		if auth.Payload != nil {
+			auth.Payload = e.ResolveData(auth.Payload)
+		}
+		resolved.Auth = &auth
+	}
+
+	if s.Proxy != nil {
+		proxy := *s.Proxy
+		proxy.Addr = e.Resolve(proxy.Addr)
+		proxy.Login = e.Resolve(proxy.Login)
+		proxy.Password = e.Resolve(proxy.Password)
+		resolved.Proxy = &proxy
+	}
+
+	return &resolved
+}
+
+func (e *Environment) resolveValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return e.Resolve(val)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = e.resolveValue(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = e.resolveValue(item)
+		}
+		return s
+	}
+	return v
+}
@@ -7,4 +7,6 @@ var (
 	ErrK8SPodNotFound = errors.New("pod not found")
 	// ErrNotConnected error - server not connected.
 	ErrNotConnected = errors.New("not connected")
+	// ErrQueryTimeout error - no response received in time.
+	ErrQueryTimeout = errors.New("query timeout")
 )
@@ -10,23 +10,47 @@ const (
 	CmdDeleteWorkspace     GUICommand = "workspace.delete"
 	CmdDuplicateWorkspace  GUICommand = "workspace.duplicate"
 	CmdExpandWorkspace     GUICommand = "workspace.expand"
+	CmdExportWorkspace     GUICommand = "workspace.export.file"
+	CmdImportWorkspace     GUICommand = "workspace.import.file"
+	CmdImportGrpcurl       GUICommand = "workspace.import.grpcurl"
 	CmdCreateServer        GUICommand = "server.create"
 	CmdUpdateServer        GUICommand = "server.update"
 	CmdUpdateServerRequest GUICommand = "server.update.request"
 	CmdLoadServer          GUICommand = "server.load"
+	CmdGenerateCerts       GUICommand = "certificates.generate"
 	CmdCreateFolder        GUICommand = "folder.create"
 	CmdUpdateFolder        GUICommand = "folder.update"
 	CmdDeleteFolder        GUICommand = "folder.delete"
+	CmdGetEnvironment      GUICommand = "environment.get"
+	CmdCreateEnvironment   GUICommand = "environment.create"
+	CmdUpdateEnvironment   GUICommand = "environment.update"
+	CmdDeleteEnvironment   GUICommand = "environment.delete"
 	CmdUpdateQuery         GUICommand = "query.update"
 	CmdRunQuery            GUICommand = "query.run"
 	CmdCancelQuery         GUICommand = "query.cancel"
 	CmdCloseStream         GUICommand = "query.close.stream"
 	CmdQueryResponse       GUICommand = "query.response"
+	CmdExportGrpcurl       GUICommand = "query.export.grpcurl"
+	CmdExportGoSnippet     GUICommand = "query.export.go"
+	CmdGetVariables        GUICommand = "variables.get"
+	CmdClearVariables      GUICommand = "variables.clear"
+	CmdGetHistory          GUICommand = "history.get"
+	CmdRunHistory          GUICommand = "history.run"
+	CmdSaveHistory         GUICommand = "history.save"
+	CmdDeleteHistory       GUICommand = "history.delete"
+	CmdClearHistory        GUICommand = "history.clear"
 	CmdDevTools            GUICommand = "dev.tools.show"
 	CmdMenuSettings        GUICommand = "menu.settings"
 	CmdMenuAbout           GUICommand = "menu.about"
+	CmdMenuExport          GUICommand = "menu.workspace.export"
+	CmdMenuImport          GUICommand = "menu.workspace.import"
+	CmdMenuEnvironments    GUICommand = "menu.environments"
+	CmdMenuHistory         GUICommand = "menu.history"
+	CmdMenuImportGrpcurl   GUICommand = "menu.grpcurl.import"
 	CmdMessageInfo         GUICommand = "message.info"
 	CmdMessageError        GUICommand = "message.error"
+	CmdConnectionState     GUICommand = "connection.state"
+	CmdSchemaChanged       GUICommand = "server.schema.changed"
 	CmdCheckUpdates        GUICommand = "check.updates"
 )
 
@@ -0,0 +1,189 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// response value sources.
+const (
+	ExtractSourceBody    = "body"
+	ExtractSourceHeader  = "header"
+	ExtractSourceTrailer = "trailer"
+)
+
+// ExtractRule rule of storing a response value into a variable.
+type ExtractRule struct {
+	Variable string `json:"variable"`
+	Source   string `json:"source"`
+	Path     string `json:"path"`
+}
+
+// ExtractRulesModel creates extraction rules from UI request.
+func ExtractRulesModel(v interface{}) ([]*ExtractRule, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.New("extract not a []interface{}")
+	}
+
+	rules := make([]*ExtractRule, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("extract rule not a map[string]interface{}")
+		}
+		r := &ExtractRule{}
+		if err := r.Model(m); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// Model creates ExtractRule from UI request.
+func (r *ExtractRule) Model(req map[string]interface{}) error {
+	if v, ok := req["variable"]; ok && v != nil {
+		if r.Variable, ok = v.(string); !ok {
+			return errors.New("variable not a string")
+		}
+	}
+	if v, ok := req["source"]; ok && v != nil {
+		if r.Source, ok = v.(string); !ok {
+			return errors.New("source not a string")
+		}
+	}
+	if v, ok := req["path"]; ok && v != nil {
+		if r.Path, ok = v.(string); !ok {
+			return errors.New("path not a string")
+		}
+	}
+
+	r.Variable = strings.TrimSpace(r.Variable)
+	r.Path = strings.TrimSpace(r.Path)
+	if r.Variable == "" {
+		return errors.New("extract variable is empty")
+	}
+
+	switch r.Source {
+	case "":
+		r.Source = ExtractSourceBody
+	case ExtractSourceBody, ExtractSourceHeader, ExtractSourceTrailer:
+	default:
+		return fmt.Errorf("unknown extract source: %s", r.Source)
+	}
+
+	return nil
+}
+
+// Extract returns the response value as a string.
+func (r *ExtractRule) Extract(resp *QueryResponse) (string, error) {
+	switch r.Source {
+	case ExtractSourceHeader, ExtractSourceTrailer:
+		md := resp.Header
+		if r.Source == ExtractSourceTrailer {
+			md = resp.Trailer
+		}
+		if v, ok := md[strings.ToLower(r.Path)]; ok && len(v) > 0 {
+			return v[0], nil
+		}
+		return "", fmt.Errorf("%s \"%s\" not found", r.Source, r.Path)
+	}
+
+	v, err := GetValueByPath(resp.Message, r.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return valueToString(v)
+}
+
+func valueToString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case nil:
+		return "", nil
+	}
+
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+// GetValueByPath returns the value of the decoded JSON by the field path,
+// e.g. "$.items[0].id", "items.0.id" or "labels['app.kubernetes.io/name']".
+func GetValueByPath(data interface{}, path string) (interface{}, error) {
+	keys, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := data
+	for _, k := range keys {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			v, ok := t[k]
+			if !ok {
+				return nil, fmt.Errorf("field \"%s\" not found in path \"%s\"", k, path)
+			}
+			cur = v
+		case []interface{}:
+			idx, err := strconv.Atoi(k)
+			if err != nil {
+				return nil, fmt.Errorf("wrong index \"%s\" in path \"%s\"", k, path)
+			}
+			if idx < 0 {
+				idx += len(t)
+			}
+			if idx < 0 || idx >= len(t) {
+				return nil, fmt.Errorf("index %s out of range in path \"%s\"", k, path)
+			}
+			cur = t[idx]
+		default:
+			return nil, fmt.Errorf("field \"%s\" not found in path \"%s\"", k, path)
+		}
+	}
+
+	return cur, nil
+}
+
+func splitPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	keys := make([]string, 0, strings.Count(path, ".")+1)
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in path \"%s\"", path)
+			}
+			keys = append(keys, strings.Trim(path[1:end], `'"`))
+			path = path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			keys = append(keys, path[:end])
+			path = path[end:]
+		}
+	}
+
+	return keys, nil
+}
@@ -25,15 +25,25 @@ type Method struct {
 	Name       string                 `json:"name"`
 	Type       string                 `json:"type"`
 	Input      []*Field               `json:"input,omitempty"`
+	HTTPRule   *HTTPRule              `json:"http_rule,omitempty"`
 	Descriptor *desc.MethodDescriptor `json:"-"`
 }
 
+// HTTPRule HTTP/JSON transcoding of the method (google.api.http).
+type HTTPRule struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Body         string `json:"body,omitempty"`
+	ResponseBody string `json:"response_body,omitempty"`
+}
+
 // LoadServerResponse server data, methods, and saved queries.
 type LoadServerResponse struct {
 	Server   *Workspace       `json:"server"`
 	Services []*Service       `json:"services"`
 	Query    *Workspace       `json:"query"`
 	Warning  []*ProtobufError `json:"warning"`
+	TLS      *TLSInfo         `json:"tls,omitempty"`
 }
 
 // Field protobuf field.
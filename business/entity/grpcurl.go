@@ -0,0 +1,478 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/forest33/warthog/pkg/structs"
+)
+
+var (
+	grpcurlBoolFlags = map[string]struct{}{
+		"plaintext": {}, "insecure": {}, "v": {}, "vv": {}, "emit-defaults": {}, "allow-unknown-fields": {},
+		"format-error": {}, "use-reflection": {}, "help": {}, "version": {}, "alts": {}, "unix": {},
+		"expand-headers": {}, "msg-template": {},
+	}
+	grpcurlValueFlags = map[string]struct{}{
+		"d": {}, "H": {}, "rpc-header": {}, "reflect-header": {}, "cacert": {}, "cert": {}, "key": {},
+		"authority": {}, "servername": {}, "user-agent": {}, "proto": {}, "import-path": {}, "protoset": {},
+		"protoset-out": {}, "proto-out-dir": {}, "format": {}, "max-time": {}, "connect-timeout": {},
+		"keepalive-time": {}, "max-msg-sz": {}, "alts-handshaker-service": {}, "alts-target-service-account": {},
+	}
+	grpcurlIgnoredFlags = map[string]struct{}{
+		"v": {}, "vv": {}, "emit-defaults": {}, "allow-unknown-fields": {}, "format-error": {}, "format": {},
+		"max-time": {}, "connect-timeout": {}, "keepalive-time": {}, "max-msg-sz": {}, "use-reflection": {},
+	}
+)
+
+// Grpcurl grpcurl command.
+type Grpcurl struct {
+	Addr        string   `json:"addr"`
+	Unix        bool     `json:"unix,omitempty"`
+	Plaintext   bool     `json:"plaintext"`
+	Insecure    bool     `json:"insecure"`
+	CACert      string   `json:"cacert,omitempty"`
+	Cert        string   `json:"cert,omitempty"`
+	Key         string   `json:"key,omitempty"`
+	Authority   string   `json:"authority,omitempty"`
+	ServerName  string   `json:"servername,omitempty"`
+	UserAgent   string   `json:"user_agent,omitempty"`
+	Headers     []string `json:"headers,omitempty"`
+	Protos      []string `json:"protos,omitempty"`
+	ImportPaths []string `json:"import_paths,omitempty"`
+	Data        string   `json:"data,omitempty"`
+	Service     string   `json:"service"`
+	Method      string   `json:"method"`
+}
+
+// GrpcurlExport exported grpcurl command.
+type GrpcurlExport struct {
+	Command  string            `json:"command"`
+	Files    map[string]string `json:"files,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// GrpcurlImportRequest grpcurl command import request.
+type GrpcurlImportRequest struct {
+	Command  string `json:"command"`
+	FolderID *int64 `json:"folder_id"`
+}
+
+// Model creates GrpcurlImportRequest from UI request.
+func (r *GrpcurlImportRequest) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["command"]; ok && v != nil {
+		if r.Command, ok = v.(string); !ok {
+			return errors.New("command not a string")
+		}
+	}
+	if v, ok := req["folder_id"]; ok && v != nil {
+		id, ok := v.(float64)
+		if !ok {
+			return errors.New("folder id not a float")
+		}
+		if id > 0 {
+			r.FolderID = structs.Ref(int64(id))
+		}
+	}
+
+	return nil
+}
+
+// NewGrpcurlExport creates the grpcurl command of the query to the server.
+func NewGrpcurlExport(server *WorkspaceItemServer, service, method, data string, metadata []string) *GrpcurlExport {
+	g := &Grpcurl{
+		Addr:      server.Addr,
+		Plaintext: server.NoTLS,
+		Insecure:  !server.NoTLS && server.Insecure,
+		Data:      data,
+		Service:   service,
+		Method:    method,
+	}
+	if path, ok := UnixSocketPath(server.Addr); ok {
+		g.Addr, g.Unix = path, true
+	}
+	if !server.NoTLS {
+		g.ServerName = server.ServerName
+	}
+	g.Authority = server.Authority
+	g.UserAgent = server.UserAgent
+	exp := &GrpcurlExport{Files: server.CertificateFiles()}
+
+	if !server.IsNativeTransport() {
+		exp.Warnings = append(exp.Warnings, fmt.Sprintf("grpcurl does not support the %s transport, the command uses the native gRPC", server.Transport))
+	}
+	if server.IsSSHEnabled() {
+		exp.Warnings = append(exp.Warnings, fmt.Sprintf("the server is reached through the SSH tunnel to %s, open the tunnel before running the command", server.SSHTunnel.RemoteAddr))
+	}
+	if server.ServiceConfig != "" {
+		exp.Warnings = append(exp.Warnings, "grpcurl does not support the service config, the retry and load balancing policies are not applied")
+	}
+	if server.Resolver.IsEnabled() {
+		exp.Warnings = append(exp.Warnings, "grpcurl does not support the resolver, the command connects to the server address")
+	}
+	if server.Proxy.IsEnabled() {
+		exp.Warnings = append(exp.Warnings, fmt.Sprintf("the server is reached through the %s proxy %s, set the HTTPS_PROXY environment variable", server.Proxy.Type, server.Proxy.Addr))
+	}
+
+	if _, ok := exp.Files[RootCertificateFile]; ok {
+		g.CACert = RootCertificateFile
+	}
+	if _, ok := exp.Files[ClientCertificateFile]; ok {
+		g.Cert = ClientCertificateFile
+		g.Key = ClientKeyFile
+	}
+	for _, name := range []string{g.CACert, g.Cert, g.Key} {
+		if name != "" {
+			exp.Warnings = append(exp.Warnings, fmt.Sprintf("save the certificate to %s", name))
+		}
+	}
+	if !server.NoTLS {
+		exp.Warnings = append(exp.Warnings, g.tlsFiles(server)...)
+	}
+
+	if !server.UseReflection {
+		g.ImportPaths = server.ImportPath
+		g.Protos = server.ProtoFiles
+	}
+
+	if server.Auth != nil {
+		switch server.Auth.Type {
+		case AuthTypeBasic:
+			auth := base64.StdEncoding.EncodeToString([]byte(server.Auth.Login + ":" + server.Auth.Password))
+			g.Headers = append(g.Headers, "authorization", "Basic "+auth)
+		case AuthTypeBearer:
+			prefix := server.Auth.HeaderPrefix
+			if prefix == "" {
+				prefix = "Bearer"
+			}
+			g.Headers = append(g.Headers, "authorization", prefix+" "+server.Auth.Token)
+		case AuthTypeJWT, AuthTypeGCE:
+			exp.Warnings = append(exp.Warnings, fmt.Sprintf("%s authentication is not exported, add the authorization header manually", server.Auth.Type))
+		}
+	}
+
+	g.Headers = append(g.Headers, metadata...)
+	exp.Command = g.String()
+
+	return exp
+}
+
+// String returns the command line.
+func (g *Grpcurl) String() string {
+	args := make([]string, 0, 16)
+	args = append(args, "grpcurl")
+
+	if g.Unix {
+		args = append(args, "-unix")
+	}
+	if g.Plaintext {
+		args = append(args, "-plaintext")
+	}
+	if g.Insecure {
+		args = append(args, "-insecure")
+	}
+	if g.CACert != "" {
+		args = append(args, "-cacert "+shellQuote(g.CACert))
+	}
+	if g.Cert != "" {
+		args = append(args, "-cert "+shellQuote(g.Cert))
+	}
+	if g.Key != "" {
+		args = append(args, "-key "+shellQuote(g.Key))
+	}
+	if g.ServerName != "" {
+		args = append(args, "-servername "+shellQuote(g.ServerName))
+	}
+	if g.Authority != "" {
+		args = append(args, "-authority "+shellQuote(g.Authority))
+	}
+	if g.UserAgent != "" {
+		args = append(args, "-user-agent "+shellQuote(g.UserAgent))
+	}
+	for i := 0; i+1 < len(g.Headers); i += 2 {
+		args = append(args, "-H "+shellQuote(g.Headers[i]+": "+g.Headers[i+1]))
+	}
+	for _, p := range g.ImportPaths {
+		args = append(args, "-import-path "+shellQuote(p))
+	}
+	for _, p := range g.Protos {
+		args = append(args, "-proto "+shellQuote(p))
+	}
+	if g.Data != "" {
+		args = append(args, "-d "+shellQuote(g.Data))
+	}
+
+	args = append(args, shellQuote(g.Addr), shellQuote(g.Service+"/"+g.Method))
+
+	return strings.Join(args, " \\\n  ")
+}
+
+// ParseGrpcurl parses the grpcurl command line.
+func ParseGrpcurl(cmd string) (*Grpcurl, []string, error) {
+	args, err := shellSplit(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(args) == 0 || !strings.HasSuffix(args[0], "grpcurl") {
+		return nil, nil, errors.New("not a grpcurl command")
+	}
+
+	var (
+		g          = &Grpcurl{}
+		warnings   []string
+		positional []string
+	)
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if idx := strings.IndexByte(name, '='); idx >= 0 {
+			name, value, hasValue = name[:idx], name[idx+1:], true
+		}
+
+		if _, ok := grpcurlBoolFlags[name]; ok {
+			if hasValue && value != "true" {
+				continue
+			}
+			switch name {
+			case "plaintext":
+				g.Plaintext = true
+			case "insecure":
+				g.Insecure = true
+			case "unix":
+				g.Unix = true
+			default:
+				if _, ok := grpcurlIgnoredFlags[name]; !ok {
+					warnings = append(warnings, fmt.Sprintf("flag -%s is not supported", name))
+				}
+			}
+			continue
+		}
+		if _, ok := grpcurlValueFlags[name]; !ok {
+			return nil, nil, fmt.Errorf("unknown flag -%s", name)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("flag -%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "d":
+			if value == "@" {
+				return nil, nil, errors.New("reading the request data from stdin is not supported")
+			}
+			g.Data = value
+		case "H", "rpc-header":
+			k, v, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, nil, fmt.Errorf("wrong header: %s", value)
+			}
+			g.Headers = append(g.Headers, strings.TrimSpace(k), strings.TrimSpace(v))
+		case "cacert":
+			g.CACert = value
+		case "cert":
+			g.Cert = value
+		case "key":
+			g.Key = value
+		case "authority":
+			g.Authority = value
+		case "servername":
+			g.ServerName = value
+		case "user-agent":
+			g.UserAgent = value
+		case "proto":
+			g.Protos = append(g.Protos, value)
+		case "import-path":
+			g.ImportPaths = append(g.ImportPaths, value)
+		default:
+			if _, ok := grpcurlIgnoredFlags[name]; !ok {
+				warnings = append(warnings, fmt.Sprintf("flag -%s is not supported", name))
+			}
+		}
+	}
+
+	if len(positional) != 2 {
+		return nil, nil, errors.New("the command must contain the address and the method, e.g. localhost:8080 pkg.Service/Method")
+	}
+
+	g.Addr = positional[0]
+	symbol := positional[1]
+	if idx := strings.LastIndexAny(symbol, "/."); idx > 0 && idx < len(symbol)-1 {
+		g.Service, g.Method = symbol[:idx], symbol[idx+1:]
+	} else {
+		return nil, nil, fmt.Errorf("wrong method: %s", symbol)
+	}
+
+	return g, warnings, nil
+}
+
+// Server returns the server of the command, the certificates are taken from the read files.
+func (g *Grpcurl) Server(files map[string]string) *WorkspaceItemServer {
+	addr := g.Addr
+	if g.Unix {
+		addr = UnixSocketAddr(g.Addr)
+	}
+
+	server := &WorkspaceItemServer{
+		Addr:              addr,
+		UseReflection:     len(g.Protos) == 0,
+		ProtoFiles:        g.Protos,
+		ImportPath:        g.ImportPaths,
+		NoTLS:             g.Plaintext,
+		Insecure:          g.Insecure,
+		RootCertificate:   files[g.CACert],
+		ClientCertificate: files[g.Cert],
+		ClientKey:         files[g.Key],
+		Authority:         g.Authority,
+		ServerName:        g.ServerName,
+		UserAgent:         g.UserAgent,
+		Auth:              &Auth{Type: AuthTypeNone},
+	}
+
+	for i := 0; i+1 < len(g.Headers); i += 2 {
+		if !strings.EqualFold(g.Headers[i], "authorization") {
+			continue
+		}
+		prefix, value, _ := strings.Cut(g.Headers[i+1], " ")
+		switch strings.ToLower(prefix) {
+		case "basic":
+			if data, err := base64.StdEncoding.DecodeString(value); err == nil {
+				login, password, _ := strings.Cut(string(data), ":")
+				server.Auth = &Auth{Type: AuthTypeBasic, Login: login, Password: password}
+			}
+		case "bearer":
+			server.Auth = &Auth{Type: AuthTypeBearer, Token: value, HeaderPrefix: prefix}
+		}
+	}
+
+	return server
+}
+
+// Query returns the query of the command.
+func (g *Grpcurl) Query() *WorkspaceItemQuery {
+	metadata := make([]interface{}, 0, len(g.Headers)/2)
+	for i := 0; i+1 < len(g.Headers); i += 2 {
+		if strings.EqualFold(g.Headers[i], "authorization") {
+			continue
+		}
+		metadata = append(metadata, map[string]interface{}{"key": g.Headers[i], "value": g.Headers[i+1]})
+	}
+
+	return &WorkspaceItemQuery{
+		Service: g.Service,
+		Method:  g.Method,
+		Request: &SavedQuery{
+			Metadata: metadata,
+			Body:     g.Data,
+		},
+	}
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:@,=+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellSplit splits the command line into arguments like a POSIX shell does.
+func shellSplit(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inArg   bool
+		escaped bool
+		quote   rune
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			if r != '\n' {
+				cur.WriteRune(r)
+				inArg = true
+			}
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in the command")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+
+	return args, nil
+}
+
+// tlsFiles sets the certificate files of the server read from the disk and returns warnings for the unsupported TLS settings.
+func (g *Grpcurl) tlsFiles(server *WorkspaceItemServer) []string {
+	var warnings []string
+
+	if g.CACert == "" {
+		g.CACert = server.RootCertificatePath
+	}
+	if g.Cert == "" && server.ClientCertificatePath != "" && server.ClientKeyPath != "" {
+		g.Cert, g.Key = server.ClientCertificatePath, server.ClientKeyPath
+	}
+	if server.PKCS12Path != "" {
+		warnings = append(warnings, "grpcurl does not support PKCS#12 bundles, convert it with openssl pkcs12 -nodes and pass -cert and -key")
+	}
+	if server.UseSystemRoots && g.CACert != "" {
+		warnings = append(warnings, "grpcurl does not trust the system CA pool together with -cacert")
+	}
+	if server.MinTLSVersion != "" || server.MaxTLSVersion != "" || len(server.CipherSuites) > 0 || len(server.ALPN) > 0 {
+		warnings = append(warnings, "TLS versions, cipher suites and ALPN are not exported")
+	}
+
+	return warnings
+}
@@ -40,7 +40,9 @@ type Error struct {
 
 // Info UI info message.
 type Info struct {
-	Message string `json:"message"`
+	Message string           `json:"message"`
+	State   *ConnectionState `json:"state,omitempty"`
+	Schema  *SchemaChanged   `json:"schema,omitempty"`
 }
 
 // Error returns error string.
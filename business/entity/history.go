@@ -0,0 +1,193 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// HistoryDefaultLimit default number of the history entries returned at once.
+const HistoryDefaultLimit = 100
+
+var (
+	// ErrHistoryNotExists error history entry not exists.
+	ErrHistoryNotExists = errors.New("history entry not exists")
+)
+
+// History executed request with its response.
+type History struct {
+	ID          int64                  `json:"id"`
+	ServerID    int64                  `json:"server_id"`
+	ServerTitle string                 `json:"server_title"`
+	Service     string                 `json:"service"`
+	Method      string                 `json:"method"`
+	Data        map[string]interface{} `json:"data"`
+	Input       interface{}            `json:"input,omitempty"`
+	Metadata    []string               `json:"metadata"`
+	Response    string                 `json:"response"`
+	Header      map[string][]string    `json:"header"`
+	Trailer     map[string][]string    `json:"trailer"`
+	Code        uint32                 `json:"code"`
+	Status      string                 `json:"status"`
+	Message     string                 `json:"message,omitempty"`
+	SpentTime   string                 `json:"spent_time"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// HistoryFilter history search conditions.
+type HistoryFilter struct {
+	ServerID int64  `json:"server_id"`
+	Service  string `json:"service"`
+	Method   string `json:"method"`
+	Status   string `json:"status"`
+	Search   string `json:"search"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+}
+
+// HistoryRequest run/save/delete history entry request.
+type HistoryRequest struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Model creates HistoryFilter from UI request.
+func (f *HistoryFilter) Model(req map[string]interface{}) error {
+	f.Limit = HistoryDefaultLimit
+	if req == nil {
+		return nil
+	}
+
+	if v, ok := req["server_id"]; ok && v != nil {
+		id, ok := v.(float64)
+		if !ok {
+			return errors.New("server id not a float")
+		}
+		f.ServerID = int64(id)
+	}
+	for key, field := range map[string]*string{
+		"service": &f.Service,
+		"method":  &f.Method,
+		"status":  &f.Status,
+		"search":  &f.Search,
+	} {
+		if v, ok := req[key]; ok && v != nil {
+			if *field, ok = v.(string); !ok {
+				return errors.New(key + " not a string")
+			}
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	if v, ok := req["limit"]; ok && v != nil {
+		limit, ok := v.(float64)
+		if !ok {
+			return errors.New("limit not a float")
+		}
+		if limit > 0 {
+			f.Limit = int(limit)
+		}
+	}
+	if v, ok := req["offset"]; ok && v != nil {
+		offset, ok := v.(float64)
+		if !ok {
+			return errors.New("offset not a float")
+		}
+		f.Offset = int(offset)
+	}
+
+	return nil
+}
+
+// Model creates HistoryRequest from UI request.
+func (r *HistoryRequest) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["id"]; ok && v != nil {
+		id, ok := v.(float64)
+		if !ok {
+			return errors.New("id not a float")
+		}
+		r.ID = int64(id)
+	}
+	if v, ok := req["title"]; ok && v != nil {
+		if r.Title, ok = v.(string); !ok {
+			return errors.New("title not a string")
+		}
+		r.Title = strings.TrimSpace(r.Title)
+	}
+	if v, ok := req["description"]; ok && v != nil {
+		if r.Description, ok = v.(string); !ok {
+			return errors.New("description not a string")
+		}
+	}
+
+	return nil
+}
+
+// GetQuery returns the history entry as a query.
+func (h *History) GetQuery() *Query {
+	return &Query{
+		ServerID: h.ServerID,
+		Service:  h.Service,
+		Method:   h.Method,
+		Data:     h.Data,
+		Input:    h.Input,
+		Metadata: h.Metadata,
+	}
+}
+
+// GetSavedQuery returns the history entry as a saved query.
+func (h *History) GetSavedQuery() *SavedQuery {
+	sq := &SavedQuery{Input: h.Input}
+	if sq.Input == nil {
+		sq.Input = h.Data
+	}
+
+	metadata := make([]interface{}, 0, len(h.Metadata)/2)
+	for i := 0; i+1 < len(h.Metadata); i += 2 {
+		metadata = append(metadata, map[string]interface{}{"key": h.Metadata[i], "value": h.Metadata[i+1]})
+	}
+	sq.Metadata = metadata
+
+	return sq
+}
+
+// SetResponses fills the history entry from the call responses.
+func (h *History) SetResponses(responses []*QueryResponse) {
+	messages := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		if resp.JsonString != "" {
+			messages = append(messages, resp.JsonString)
+		}
+		if len(resp.Header) > 0 {
+			h.Header = resp.Header
+		}
+		if len(resp.Trailer) > 0 {
+			h.Trailer = resp.Trailer
+		}
+		h.SpentTime = resp.SpentTime
+		if resp.Error != nil {
+			h.Code = resp.Error.Code
+			h.Status = resp.Error.CodeDescription
+			h.Message = resp.Error.Message
+		}
+	}
+
+	if h.Status == "" {
+		h.Status = codes.OK.String()
+	}
+
+	switch len(messages) {
+	case 0:
+	case 1:
+		h.Response = messages[0]
+	default:
+		h.Response = "[\n" + strings.Join(messages, ",\n") + "\n]"
+	}
+}
@@ -3,6 +3,8 @@ package entity
 const (
 	// MsgCreatingPortForward message creating port forwarding.
 	MsgCreatingPortForward = "Creating port forwarding..."
+	// MsgCreatingSSHTunnel message creating SSH tunnel.
+	MsgCreatingSSHTunnel = "Creating SSH tunnel..."
 	// MsgConnectingServer message connecting to server.
 	MsgConnectingServer = "Connecting to server..."
 	// MsgServerReflectionInfo message getting information about services.
@@ -0,0 +1,73 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// ProxyTypeNone connect directly.
+	ProxyTypeNone = "none"
+	// ProxyTypeHTTP HTTP CONNECT proxy.
+	ProxyTypeHTTP = "http"
+	// ProxyTypeSOCKS5 SOCKS5 proxy.
+	ProxyTypeSOCKS5 = "socks5"
+)
+
+// Proxy proxy server settings, the server proxy with empty type uses the proxy from the settings.
+type Proxy struct {
+	Type     string `json:"type,omitempty"`
+	Addr     string `json:"addr,omitempty"`
+	Login    string `json:"login,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// Model creates Proxy from UI request.
+func (p *Proxy) Model(proxy map[string]interface{}) error {
+	if proxy == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := proxy["type"]; ok && v != nil {
+		if p.Type, ok = v.(string); !ok {
+			return errors.New("proxy type not a string")
+		}
+		switch p.Type {
+		case "", ProxyTypeNone, ProxyTypeHTTP, ProxyTypeSOCKS5:
+		default:
+			return fmt.Errorf("unknown proxy type: %s", p.Type)
+		}
+	}
+	if v, ok := proxy["addr"]; ok && v != nil {
+		if p.Addr, ok = v.(string); !ok {
+			return errors.New("proxy address not a string")
+		}
+	}
+	if v, ok := proxy["login"]; ok && v != nil {
+		if p.Login, ok = v.(string); !ok {
+			return errors.New("proxy login not a string")
+		}
+	}
+	if v, ok := proxy["password"]; ok && v != nil {
+		if p.Password, ok = v.(string); !ok {
+			return errors.New("proxy password not a string")
+		}
+	}
+
+	if p.IsEnabled() && p.Addr == "" {
+		return errors.New("empty proxy address")
+	}
+
+	return nil
+}
+
+// IsEnabled checks whether the connection goes through the proxy.
+func (p *Proxy) IsEnabled() bool {
+	return p != nil && (p.Type == ProxyTypeHTTP || p.Type == ProxyTypeSOCKS5)
+}
+
+// IsDefault checks whether the server uses the proxy from the settings.
+func (p *Proxy) IsDefault() bool {
+	return p == nil || p.Type == ""
+}
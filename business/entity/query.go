@@ -30,17 +30,27 @@ const (
 	TypeMessage  = "message"
 )
 
-// Query gRPC request.
+// Query gRPC request, the request with the session ID of the running client stream is sent to the stream.
 type Query struct {
-	ServerID int64
-	Service  string
-	Method   string
-	Data     map[string]interface{}
-	Metadata []string
+	SessionID   uint64
+	ServerID    int64
+	Service     string
+	Method      string
+	Data        map[string]interface{}
+	Input       interface{}
+	Metadata    []string
+	Extract     []*ExtractRule
+	Assertions  []*Assertion
+	Compression string
 }
 
-// QueryResponse gRPC response.
+// QueryResponse gRPC response of the session.
 type QueryResponse struct {
+	SessionID  uint64              `json:"session_id"`
+	ServerID   int64               `json:"server_id,omitempty"`
+	Service    string              `json:"service,omitempty"`
+	Method     string              `json:"method,omitempty"`
+	Last       bool                `json:"last,omitempty"`
 	Time       string              `json:"time"`
 	JsonString string              `json:"json_string"`
 	SpentTime  string              `json:"spent_time"`
@@ -49,6 +59,28 @@ type QueryResponse struct {
 	Error      *Error              `json:"error"`
 	Sent       uint                `json:"sent"`
 	Received   uint                `json:"received"`
+	Attempts   uint                `json:"attempts,omitempty"`
+	Variables  map[string]string   `json:"variables,omitempty"`
+	ExtractErr string              `json:"extract_error,omitempty"`
+	Assertions []*AssertionResult  `json:"assertions,omitempty"`
+	Message    interface{}         `json:"-"`
+}
+
+// SessionRequest UI request to cancel or close the stream of the session, all sessions if the ID is zero.
+type SessionRequest struct {
+	SessionID uint64
+}
+
+// Model creates SessionRequest from UI request.
+func (r *SessionRequest) Model(payload map[string]interface{}) error {
+	if v, ok := payload["session_id"]; ok && v != nil {
+		id, ok := v.(float64)
+		if !ok {
+			return errors.New("session id not a float")
+		}
+		r.SessionID = uint64(id)
+	}
+	return nil
 }
 
 // Model creates Query from UI request.
@@ -57,6 +89,13 @@ func (r *Query) Model(server map[string]interface{}) error {
 		return errors.New("empty data")
 	}
 
+	if v, ok := server["session_id"]; ok && v != nil {
+		if id, ok := v.(float64); !ok {
+			return errors.New("session id not a float")
+		} else {
+			r.SessionID = uint64(id)
+		}
+	}
 	if v, ok := server["server_id"]; ok && v != nil {
 		if id, ok := v.(float64); !ok {
 			return errors.New("server id not a float")
@@ -79,6 +118,26 @@ func (r *Query) Model(server map[string]interface{}) error {
 			return errors.New("data not a map[string]interface{}")
 		}
 	}
+	if v, ok := server["extract"]; ok && v != nil {
+		var err error
+		if r.Extract, err = ExtractRulesModel(v); err != nil {
+			return err
+		}
+	}
+	if v, ok := server["input"]; ok && v != nil {
+		r.Input = v
+	}
+	if v, ok := server["assertions"]; ok && v != nil {
+		var err error
+		if r.Assertions, err = AssertionsModel(v); err != nil {
+			return err
+		}
+	}
+	if v, ok := server["compression"]; ok && v != nil {
+		if r.Compression, ok = v.(string); !ok {
+			return errors.New("compression not a string")
+		}
+	}
 	if v, ok := server["metadata"]; ok {
 		if m, ok := v.(map[string]interface{}); !ok {
 			return errors.New("metadata not a map[string]interface{}")
@@ -0,0 +1,166 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ResolverTypeNone dials the server address.
+	ResolverTypeNone = "none"
+	// ResolverTypeStatic round-robin over the static list of backends.
+	ResolverTypeStatic = "static"
+	// ResolverTypeDNSSRV round-robin over the targets of the DNS SRV record.
+	ResolverTypeDNSSRV = "dns-srv"
+	// ResolverTypeFile round-robin over the backends listed in the file.
+	ResolverTypeFile = "file"
+)
+
+// Resolver name resolution of the server, the server address is still used as the authority and the TLS server name.
+type Resolver struct {
+	// Type of the resolver, the server address is dialed if empty.
+	Type string `json:"type,omitempty"`
+	// Addresses host:port of the backends of the static resolver.
+	Addresses []string `json:"addresses,omitempty"`
+	// SRVName DNS SRV record name, e.g. _grpc._tcp.example.com.
+	SRVName string `json:"srv_name,omitempty"`
+	// File absolute path to the file with one host:port per line, the file is read again on reconnect.
+	File string `json:"file,omitempty"`
+	// Hosts curl --resolve style overrides host:port:address, the port may be *.
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// Model creates Resolver from UI request.
+func (r *Resolver) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["type"]; ok && v != nil {
+		if r.Type, ok = v.(string); !ok {
+			return errors.New("resolver type not a string")
+		}
+	}
+	if v, ok := req["srv_name"]; ok && v != nil {
+		if r.SRVName, ok = v.(string); !ok {
+			return errors.New("SRV name not a string")
+		}
+	}
+	if v, ok := req["file"]; ok && v != nil {
+		if r.File, ok = v.(string); !ok {
+			return errors.New("resolver file not a string")
+		}
+	}
+	for _, f := range []struct {
+		name  string
+		title string
+		value *[]string
+	}{
+		{"addresses", "addresses", &r.Addresses},
+		{"hosts", "hosts", &r.Hosts},
+	} {
+		v, ok := req[f.name]
+		if !ok || v == nil {
+			continue
+		}
+		list, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s not an array", f.title)
+		}
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s item not a string", f.title)
+			}
+			if s = strings.TrimSpace(s); s != "" {
+				*f.value = append(*f.value, s)
+			}
+		}
+	}
+
+	return r.validate()
+}
+
+func (r *Resolver) validate() error {
+	switch r.Type {
+	case "", ResolverTypeNone:
+	case ResolverTypeStatic:
+		if len(r.Addresses) == 0 {
+			return errors.New("empty list of the backends")
+		}
+		for _, addr := range r.Addresses {
+			if _, _, err := net.SplitHostPort(addr); err != nil {
+				return fmt.Errorf("wrong backend address %s: %v", addr, err)
+			}
+		}
+	case ResolverTypeDNSSRV:
+		if r.SRVName == "" {
+			return errors.New("empty SRV record name")
+		}
+	case ResolverTypeFile:
+		if r.File == "" {
+			return errors.New("empty resolver file")
+		}
+	default:
+		return fmt.Errorf("unknown resolver type: %s", r.Type)
+	}
+
+	for _, h := range r.Hosts {
+		if _, _, _, err := parseHostOverride(h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsEnabled checks whether the server address is resolved by the resolver.
+func (r *Resolver) IsEnabled() bool {
+	return r != nil && ((r.Type != "" && r.Type != ResolverTypeNone) || len(r.Hosts) > 0)
+}
+
+// Override returns the address with the host replaced by the host override.
+func (r *Resolver) Override(addr string) string {
+	if r == nil || len(r.Hosts) == 0 {
+		return addr
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	for _, h := range r.Hosts {
+		oHost, oPort, oAddr, err := parseHostOverride(h)
+		if err != nil || !strings.EqualFold(oHost, host) || (oPort != "*" && oPort != port) {
+			continue
+		}
+		return net.JoinHostPort(oAddr, port)
+	}
+
+	return addr
+}
+
+// parseHostOverride parses host:port:address, the IPv6 address may be enclosed in brackets.
+func parseHostOverride(s string) (host, port, addr string, err error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("wrong host override %s, expected host:port:address", s)
+	}
+
+	host, port, addr = parts[0], parts[1], strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+	if host == "" {
+		return "", "", "", fmt.Errorf("empty host of the host override %s", s)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil && port != "*" {
+		return "", "", "", fmt.Errorf("wrong port of the host override %s", s)
+	}
+	if net.ParseIP(addr) == nil {
+		return "", "", "", fmt.Errorf("wrong address of the host override %s", s)
+	}
+
+	return host, port, addr, nil
+}
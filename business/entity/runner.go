@@ -0,0 +1,270 @@
+// Package entity provides entities for business logic.
+package entity
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/forest33/warthog/pkg/structs"
+)
+
+// run report formats.
+const (
+	RunReportFormatJSON  = "json"
+	RunReportFormatJUnit = "junit"
+)
+
+// RunReport result of running a workspace folder or server.
+type RunReport struct {
+	Name      string       `json:"name"`
+	StartedAt time.Time    `json:"started_at"`
+	Duration  string       `json:"duration"`
+	Total     int          `json:"total"`
+	Passed    int          `json:"passed"`
+	Failed    int          `json:"failed"`
+	Errors    int          `json:"errors"`
+	Results   []*RunResult `json:"results"`
+}
+
+// RunResult result of running a saved query.
+type RunResult struct {
+	QueryID           int64            `json:"query_id"`
+	Title             string           `json:"title"`
+	Breadcrumb        []string         `json:"breadcrumb"`
+	Service           string           `json:"service"`
+	Method            string           `json:"method"`
+	Code              uint32           `json:"code"`
+	Status            string           `json:"status"`
+	Message           string           `json:"message,omitempty"`
+	SpentTime         string           `json:"spent_time"`
+	Passed            bool             `json:"passed"`
+	RunError          string           `json:"run_error,omitempty"`
+	Responses         []*QueryResponse `json:"responses"`
+	AssertionFailures []string         `json:"assertion_failures,omitempty"`
+}
+
+// AddResult adds the query result to the report.
+func (r *RunReport) AddResult(res *RunResult) {
+	r.Results = append(r.Results, res)
+	r.Total++
+
+	switch {
+	case res.RunError != "":
+		r.Errors++
+	case res.Passed:
+		r.Passed++
+	default:
+		r.Failed++
+	}
+}
+
+// IsPassed returns true if all queries passed.
+func (r *RunReport) IsPassed() bool {
+	return r.Failed == 0 && r.Errors == 0
+}
+
+// SetResponses fills the result status from the query responses.
+func (r *RunResult) SetResponses(responses []*QueryResponse, err error) {
+	r.Responses = responses
+	r.Passed = true
+	r.Code = uint32(codes.OK)
+	r.Status = codes.OK.String()
+
+	var (
+		spent          time.Duration
+		asserted       bool
+		statusAsserted bool
+		failed         []string
+	)
+	for _, resp := range responses {
+		if d, err := time.ParseDuration(resp.SpentTime); err == nil && d > spent {
+			spent = d
+		}
+		if resp.Error != nil {
+			r.Passed = false
+			r.Code = resp.Error.Code
+			r.Status = resp.Error.CodeDescription
+			r.Message = resp.Error.Message
+		}
+		for _, a := range resp.Assertions {
+			asserted = true
+			if a.Source == AssertSourceStatus {
+				statusAsserted = true
+			}
+			if !a.Passed {
+				failed = append(failed, a.String())
+			}
+		}
+	}
+	r.SpentTime = spent.String()
+
+	// the status assertions decide whether the error is expected, other assertions can only fail the query
+	if asserted {
+		if statusAsserted {
+			r.Passed = len(failed) == 0
+		} else {
+			r.Passed = r.Passed && len(failed) == 0
+		}
+		r.AssertionFailures = failed
+	}
+
+	if err != nil {
+		r.Passed = false
+		r.RunError = err.Error()
+	}
+}
+
+// Marshal returns the report in the given format.
+func (r *RunReport) Marshal(format string) ([]byte, error) {
+	switch format {
+	case RunReportFormatJSON:
+		return json.MarshalIndent(r, "", "  ")
+	case RunReportFormatJUnit:
+		data, err := xml.MarshalIndent(r.junit(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), data...), nil
+	}
+
+	return nil, CheckRunReportFormat(format)
+}
+
+// CheckRunReportFormat checks whether the report format is supported.
+func CheckRunReportFormat(format string) error {
+	switch format {
+	case RunReportFormatJSON, RunReportFormatJUnit:
+		return nil
+	}
+	return fmt.Errorf("unknown report format: %s", format)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Cases     []*junitTestCase `xml:"testcase"`
+	duration  time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+func (r *RunReport) junit() *junitTestSuites {
+	suites := &junitTestSuites{
+		Name:     r.Name,
+		Tests:    r.Total,
+		Failures: r.Failed,
+		Errors:   r.Errors,
+		Time:     junitTime(r.Duration),
+		Suites:   make([]*junitTestSuite, 0, 1),
+	}
+
+	suiteMap := make(map[string]*junitTestSuite, 1)
+	for _, res := range r.Results {
+		var name string
+		if len(res.Breadcrumb) > 1 {
+			name = strings.Join(res.Breadcrumb[:len(res.Breadcrumb)-1], WorkspaceBreadcrumbSeparator)
+		}
+		suite, ok := suiteMap[name]
+		if !ok {
+			suite = &junitTestSuite{
+				Name:      name,
+				Timestamp: r.StartedAt.Format("2006-01-02T15:04:05"),
+				Cases:     make([]*junitTestCase, 0, 1),
+			}
+			suiteMap[name] = suite
+			suites.Suites = append(suites.Suites, suite)
+		}
+
+		tc := &junitTestCase{
+			Name:      res.Title,
+			ClassName: fmt.Sprintf("%s.%s", res.Service, res.Method),
+			Time:      junitTime(res.SpentTime),
+			SystemOut: res.systemOut(),
+		}
+
+		switch {
+		case res.RunError != "":
+			tc.Error = &junitMessage{Message: res.RunError, Type: "RunError"}
+			suite.Errors++
+		case !res.Passed && len(res.AssertionFailures) > 0:
+			tc.Failure = &junitMessage{Message: strings.Join(res.AssertionFailures, "; "), Type: "AssertionFailure"}
+			suite.Failures++
+		case !res.Passed:
+			tc.Failure = &junitMessage{Message: fmt.Sprintf("%d %s: %s", res.Code, res.Status, res.Message), Type: res.Status}
+			suite.Failures++
+		}
+
+		if d, err := time.ParseDuration(res.SpentTime); err == nil {
+			suite.duration += d
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, s := range suites.Suites {
+		s.Time = junitTime(s.duration.String())
+	}
+
+	return suites
+}
+
+func (r *RunResult) systemOut() string {
+	sb := strings.Builder{}
+	for _, resp := range r.Responses {
+		writeMetadata(&sb, "header", resp.Header)
+		if resp.JsonString != "" {
+			sb.WriteString(resp.JsonString)
+			sb.WriteString("\n")
+		}
+		writeMetadata(&sb, "trailer", resp.Trailer)
+	}
+	return sb.String()
+}
+
+func writeMetadata(sb *strings.Builder, prefix string, md map[string][]string) {
+	keys := structs.Keys(md)
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("%s %s: %s\n", prefix, k, strings.Join(md[k], ", ")))
+	}
+}
+
+func junitTime(d string) string {
+	v, err := time.ParseDuration(d)
+	if err != nil {
+		return "0"
+	}
+	return fmt.Sprintf("%.3f", v.Seconds())
+}
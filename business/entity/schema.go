@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const protoFileExt = ".proto"
+
+// SchemaChanged notification of the changed services of the server, the GUI loads the server again.
+type SchemaChanged struct {
+	ServerID int64 `json:"server_id"`
+}
+
+// ProtobufHash returns the hash of the protobuf files of the server with their sizes and modification times,
+// the protobuf files under the import paths are included as they may be imported.
+func (s *WorkspaceItemServer) ProtobufHash() (string, error) {
+	h := sha256.New()
+
+	addFile := func(path string, info fs.FileInfo) {
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+
+	for _, p := range s.ProtoFiles {
+		info, err := os.Stat(p)
+		if err != nil {
+			return "", err
+		}
+		addFile(p, info)
+	}
+
+	for _, root := range s.ImportPath {
+		_, _ = fmt.Fprintf(h, "%s\n", root)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != protoFileExt {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			addFile(path, info)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SchemaHash returns the hash of the protobuf files defining the services and their dependencies.
+func SchemaHash(services []*Service) (string, error) {
+	files := make(map[string]*desc.FileDescriptor)
+
+	var addFile func(fd *desc.FileDescriptor)
+	addFile = func(fd *desc.FileDescriptor) {
+		if _, ok := files[fd.GetName()]; ok {
+			return
+		}
+		files[fd.GetName()] = fd
+		for _, dep := range fd.GetDependencies() {
+			addFile(dep)
+		}
+	}
+
+	for _, s := range services {
+		for _, m := range s.Methods {
+			if m.Descriptor != nil {
+				addFile(m.Descriptor.GetFile())
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		// the comments are not a part of the schema
+		fdp := proto.Clone(files[name].AsFileDescriptorProto()).(*descriptorpb.FileDescriptorProto)
+		fdp.SourceCodeInfo = nil
+		buf, err := proto.MarshalOptions{Deterministic: true}.Marshal(fdp)
+		if err != nil {
+			return "", err
+		}
+		_, _ = h.Write(buf)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -9,19 +9,25 @@ import (
 
 // Settings application settings.
 type Settings struct {
-	WindowWidth           int   `json:"window_width"`
-	WindowHeight          int   `json:"window_height"`
-	WindowX               *int  `json:"window_x"`
-	WindowY               *int  `json:"window_y"`
-	SingleInstance        *bool `json:"single_instance"`
-	ConnectTimeout        *int  `json:"connect_timeout"`
-	RequestTimeout        *int  `json:"request_timeout"`
-	K8SRequestTimeout     *int  `json:"k8s_request_timeout"`
-	NonBlockingConnection *bool `json:"non_blocking_connection"`
-	SortMethodsByName     *bool `json:"sort_methods_by_name"`
-	MaxLoopDepth          *int  `json:"max_loop_depth"`
-	EmitDefaults          *bool `json:"emit_defaults"`
-	CheckUpdates          *bool `json:"check_updates"`
+	WindowWidth           int          `json:"window_width"`
+	WindowHeight          int          `json:"window_height"`
+	WindowX               *int         `json:"window_x"`
+	WindowY               *int         `json:"window_y"`
+	SingleInstance        *bool        `json:"single_instance"`
+	ConnectTimeout        *int         `json:"connect_timeout"`
+	RequestTimeout        *int         `json:"request_timeout"`
+	K8SRequestTimeout     *int         `json:"k8s_request_timeout"`
+	NonBlockingConnection *bool        `json:"non_blocking_connection"`
+	SortMethodsByName     *bool        `json:"sort_methods_by_name"`
+	MaxLoopDepth          *int         `json:"max_loop_depth"`
+	EmitDefaults          *bool        `json:"emit_defaults"`
+	CheckUpdates          *bool        `json:"check_updates"`
+	Proxy                 *Proxy       `json:"proxy"`
+	CertExpiryWarningDays *int         `json:"cert_expiry_warning_days"`
+	CallOptions           *CallOptions `json:"call_options"`
+	AutoReconnect         *bool        `json:"auto_reconnect"`
+	ReconnectBaseDelay    *int         `json:"reconnect_base_delay"`
+	ReconnectMaxDelay     *int         `json:"reconnect_max_delay"`
 }
 
 // DefaultSettings settings by default.
@@ -39,6 +45,15 @@ var DefaultSettings = &Settings{
 	MaxLoopDepth:          structs.Ref(10),
 	EmitDefaults:          structs.Ref(false),
 	CheckUpdates:          structs.Ref(true),
+	Proxy:                 &Proxy{Type: ProxyTypeNone},
+	CertExpiryWarningDays: structs.Ref(30),
+	CallOptions: &CallOptions{
+		Compression:    CompressionNone,
+		MaxRecvMsgSize: 64,
+	},
+	AutoReconnect:      structs.Ref(true),
+	ReconnectBaseDelay: structs.Ref(1),
+	ReconnectMaxDelay:  structs.Ref(120),
 }
 
 // Model creates Settings from UI request.
@@ -110,6 +125,63 @@ func (s *Settings) Model(payload map[string]interface{}) error {
 		}
 		s.CheckUpdates = &b
 	}
+	if v, ok := payload["cert_expiry_warning_days"]; ok && v != nil {
+		f, ok := v.(float64)
+		if !ok {
+			return errors.New("certificate expiry warning days not a float")
+		}
+		s.CertExpiryWarningDays = structs.Ref(int(f))
+	}
+	if v, ok := payload["auto_reconnect"]; ok && v != nil {
+		b, ok := v.(bool)
+		if !ok {
+			return errors.New("auto reconnect not a bool")
+		}
+		s.AutoReconnect = &b
+	}
+	if v, ok := payload["reconnect_base_delay"]; ok && v != nil {
+		f, ok := v.(float64)
+		if !ok {
+			return errors.New("reconnect base delay not a float")
+		}
+		s.ReconnectBaseDelay = structs.Ref(int(f))
+	}
+	if v, ok := payload["reconnect_max_delay"]; ok && v != nil {
+		f, ok := v.(float64)
+		if !ok {
+			return errors.New("reconnect max delay not a float")
+		}
+		s.ReconnectMaxDelay = structs.Ref(int(f))
+	}
+	if s.ReconnectBaseDelay != nil && s.ReconnectMaxDelay != nil && *s.ReconnectMaxDelay > 0 && *s.ReconnectBaseDelay > *s.ReconnectMaxDelay {
+		return errors.New("reconnect base delay is greater than max delay")
+	}
+	if v, ok := payload["call_options"]; ok && v != nil {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.New("call options not a map")
+		}
+		s.CallOptions = &CallOptions{}
+		if err := s.CallOptions.Model(m); err != nil {
+			return err
+		}
+		if s.CallOptions.Compression == "" {
+			s.CallOptions.Compression = CompressionNone
+		}
+	}
+	if v, ok := payload["proxy"]; ok && v != nil {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.New("proxy not a map")
+		}
+		s.Proxy = &Proxy{}
+		if err := s.Proxy.Model(m); err != nil {
+			return err
+		}
+		if s.Proxy.Type == "" {
+			s.Proxy.Type = ProxyTypeNone
+		}
+	}
 
 	return nil
 }
@@ -121,3 +193,8 @@ func (s *Settings) IsEmitDefaults() bool {
 func (s *Settings) IsCheckUpdates() bool {
 	return s.CheckUpdates != nil && *s.CheckUpdates
 }
+
+// IsAutoReconnect checks whether the idle connection is reconnected without waiting for a call.
+func (s *Settings) IsAutoReconnect() bool {
+	return s.AutoReconnect == nil || *s.AutoReconnect
+}
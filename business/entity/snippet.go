@@ -0,0 +1,9 @@
+// Package entity provides entities for business logic.
+package entity
+
+// CodeSnippet generated client code of the query.
+type CodeSnippet struct {
+	Code     string            `json:"code"`
+	Files    map[string]string `json:"files,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"errors"
+	"net"
+	"strconv"
+)
+
+// SSHTunnelControl SSH tunnel control.
+type SSHTunnelControl interface {
+	Close()
+}
+
+// SSHTunnel SSH tunnel through the jump host.
+type SSHTunnel struct {
+	// Enabled SSH tunnel.
+	Enabled bool `json:"enabled"`
+	// Host is the jump host address, the port is 22 by default.
+	Host string `json:"host"`
+	// User is the SSH user.
+	User string `json:"user"`
+	// PrivateKeyFile absolute path to the private key file.
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	// Passphrase of the private key.
+	Passphrase string `json:"passphrase,omitempty"`
+	// UseAgent authenticate by the keys of the SSH agent.
+	UseAgent bool `json:"use_agent,omitempty"`
+	// KnownHostsFile absolute path to the known_hosts file, ~/.ssh/known_hosts by default.
+	KnownHostsFile string `json:"known_hosts_file,omitempty"`
+	// InsecureIgnoreHostKey disables the host key checking.
+	InsecureIgnoreHostKey bool `json:"insecure_ignore_host_key,omitempty"`
+	// LocalPort is the local port that will be forwarded to the RemoteAddr.
+	LocalPort uint16 `json:"local_port"`
+	// RemoteAddr is the gRPC server address as seen from the jump host.
+	RemoteAddr string `json:"remote_addr"`
+	// ErrHandler error handler.
+	ErrHandler func(err error) `json:"-"`
+}
+
+// Model creates SSHTunnel from UI request.
+func (t *SSHTunnel) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["enabled"]; ok && v != nil {
+		if t.Enabled, ok = v.(bool); !ok {
+			return errors.New("enabled not a boolean")
+		}
+	}
+	if v, ok := req["host"]; ok && v != nil {
+		if t.Host, ok = v.(string); !ok {
+			return errors.New("host not a string")
+		}
+	}
+	if v, ok := req["user"]; ok && v != nil {
+		if t.User, ok = v.(string); !ok {
+			return errors.New("user not a string")
+		}
+	}
+	if v, ok := req["private_key_file"]; ok && v != nil {
+		if t.PrivateKeyFile, ok = v.(string); !ok {
+			return errors.New("private key file not a string")
+		}
+	}
+	if v, ok := req["passphrase"]; ok && v != nil {
+		if t.Passphrase, ok = v.(string); !ok {
+			return errors.New("passphrase not a string")
+		}
+	}
+	if v, ok := req["use_agent"]; ok && v != nil {
+		if t.UseAgent, ok = v.(bool); !ok {
+			return errors.New("use agent not a boolean")
+		}
+	}
+	if v, ok := req["known_hosts_file"]; ok && v != nil {
+		if t.KnownHostsFile, ok = v.(string); !ok {
+			return errors.New("known hosts file not a string")
+		}
+	}
+	if v, ok := req["insecure_ignore_host_key"]; ok && v != nil {
+		if t.InsecureIgnoreHostKey, ok = v.(bool); !ok {
+			return errors.New("insecure ignore host key not a boolean")
+		}
+	}
+	if v, ok := req["local_port"]; ok && v != nil {
+		if lp, ok := v.(string); !ok {
+			return errors.New("local port not a string")
+		} else {
+			port, err := strconv.ParseUint(lp, 10, 16)
+			if err != nil {
+				return err
+			}
+			t.LocalPort = uint16(port)
+		}
+	}
+	if v, ok := req["remote_addr"]; ok && v != nil {
+		if t.RemoteAddr, ok = v.(string); !ok {
+			return errors.New("remote address not a string")
+		}
+	}
+
+	if !t.Enabled {
+		return nil
+	}
+
+	if t.Host == "" || t.User == "" {
+		return errors.New("empty SSH host or user")
+	}
+	if t.PrivateKeyFile == "" && !t.UseAgent {
+		return errors.New("no SSH private key or agent")
+	}
+	if _, _, err := net.SplitHostPort(t.RemoteAddr); err != nil {
+		return errors.New("wrong SSH tunnel remote address")
+	}
+
+	return nil
+}
+
+// HostAddr returns the jump host address with the port.
+func (t *SSHTunnel) HostAddr() string {
+	if _, _, err := net.SplitHostPort(t.Host); err != nil {
+		return net.JoinHostPort(t.Host, "22")
+	}
+	return t.Host
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TLSVersions TLS versions by the names used in the server settings.
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSInfo negotiated parameters of the TLS connection and the certificate chain of the server.
+type TLSInfo struct {
+	Version      string             `json:"version"`
+	CipherSuite  string             `json:"cipher_suite"`
+	ALPN         string             `json:"alpn,omitempty"`
+	ServerName   string             `json:"server_name"`
+	Certificates []*CertificateInfo `json:"certificates"`
+	Warnings     []string           `json:"warnings,omitempty"`
+}
+
+// CertificateInfo certificate of the server chain, the leaf certificate goes first.
+type CertificateInfo struct {
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	DNSNames    []string  `json:"dns_names,omitempty"`
+	IPAddresses []string  `json:"ip_addresses,omitempty"`
+	NotBefore   time.Time `json:"not_before"`
+	NotAfter    time.Time `json:"not_after"`
+	Serial      string    `json:"serial"`
+	Fingerprint string    `json:"fingerprint"`
+	IsCA        bool      `json:"is_ca"`
+}
+
+// ExpiryWarning returns the warning if the certificate is expired or expires within the days.
+func (c *CertificateInfo) ExpiryWarning(now time.Time, days int) string {
+	switch {
+	case now.After(c.NotAfter):
+		return fmt.Sprintf("certificate %s expired on %s", c.Subject, c.NotAfter.Format(time.DateOnly))
+	case now.Before(c.NotBefore):
+		return fmt.Sprintf("certificate %s is not valid before %s", c.Subject, c.NotBefore.Format(time.DateOnly))
+	case days > 0 && c.NotAfter.Before(now.AddDate(0, 0, days)):
+		return fmt.Sprintf("certificate %s expires in %d day(s) on %s", c.Subject, int(c.NotAfter.Sub(now).Hours()/24), c.NotAfter.Format(time.DateOnly))
+	}
+	return ""
+}
+
+// TLSCipherSuite returns the ID of the cipher suite by the IANA name, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func TLSCipherSuite(name string) (uint16, bool) {
+	for _, list := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, cs := range list {
+			if cs.Name == name {
+				return cs.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// validateTLS checks the TLS versions and cipher suites of the server.
+func (s *WorkspaceItemServer) validateTLS() error {
+	for _, v := range []string{s.MinTLSVersion, s.MaxTLSVersion} {
+		if _, ok := TLSVersions[v]; !ok && v != "" {
+			return fmt.Errorf("unknown TLS version: %s", v)
+		}
+	}
+	if s.MinTLSVersion != "" && s.MaxTLSVersion != "" && TLSVersions[s.MinTLSVersion] > TLSVersions[s.MaxTLSVersion] {
+		return fmt.Errorf("minimum TLS version %s is greater than maximum %s", s.MinTLSVersion, s.MaxTLSVersion)
+	}
+	for _, name := range s.CipherSuites {
+		if _, ok := TLSCipherSuite(name); !ok {
+			return fmt.Errorf("unknown cipher suite: %s", name)
+		}
+	}
+	if s.PKCS12Path != "" && (s.ClientCertificate != "" || s.ClientCertificatePath != "") {
+		return errors.New("use either the PKCS#12 bundle or the client certificate")
+	}
+	return nil
+}
@@ -1,10 +1,70 @@
 package entity
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/forest33/warthog/pkg/structs"
+)
+
+// ExportFormatVersion current version of the export file format.
+const ExportFormatVersion = 1
+
+// import strategies for title collisions.
+const (
+	ImportStrategySkip      = "skip"
+	ImportStrategyOverwrite = "overwrite"
+	ImportStrategyRename    = "rename"
+)
+
+// credentials redaction modes.
+const (
+	ExportRedactNone        = "none"
+	ExportRedactStrip       = "strip"
+	ExportRedactPlaceholder = "placeholder"
+
+	ExportSecretPlaceholder = "REDACTED"
+)
 
 // ExportRequest export request.
 type ExportRequest struct {
-	Path string `json:"path"`
+	Path   string `json:"path"`
+	ID     *int64 `json:"id"`
+	Redact string `json:"redact"`
+}
+
+// WorkspaceExport export file envelope.
+type WorkspaceExport struct {
+	FormatVersion int          `json:"format_version"`
+	AppVersion    string       `json:"app_version"`
+	ExportedAt    time.Time    `json:"exported_at"`
+	Redact        string       `json:"redact"`
+	Items         []*Workspace `json:"items"`
+}
+
+// ImportRequest import request.
+type ImportRequest struct {
+	Path     string `json:"path"`
+	Strategy string `json:"strategy"`
+}
+
+// ImportSummary summary of the imported workspace items.
+type ImportSummary struct {
+	Folders     int      `json:"folders"`
+	Servers     int      `json:"servers"`
+	Queries     int      `json:"queries"`
+	Merged      int      `json:"merged"`
+	Skipped     []string `json:"skipped"`
+	Overwritten []string `json:"overwritten"`
+	Renamed     []string `json:"renamed"`
+}
+
+// ImportResponse import response.
+type ImportResponse struct {
+	Summary *ImportSummary       `json:"summary"`
+	Tree    []*WorkspaceTreeNode `json:"tree"`
 }
 
 // Model creates ExportRequest from UI request.
@@ -18,6 +78,173 @@ func (r *ExportRequest) Model(req map[string]interface{}) error {
 			return errors.New("path not a string")
 		}
 	}
+	if v, ok := req["id"]; ok && v != nil {
+		id, ok := v.(float64)
+		if !ok {
+			return errors.New("id not a float")
+		}
+		r.ID = structs.Ref(int64(id))
+	}
+
+	r.Redact = ExportRedactNone
+	if v, ok := req["redact"]; ok && v != nil {
+		if r.Redact, ok = v.(string); !ok {
+			return errors.New("redact not a string")
+		}
+	}
+
+	switch r.Redact {
+	case ExportRedactNone, ExportRedactStrip, ExportRedactPlaceholder:
+	default:
+		return fmt.Errorf("unknown redact mode: %s", r.Redact)
+	}
+
+	return nil
+}
+
+// Model creates ImportRequest from UI request.
+func (r *ImportRequest) Model(req map[string]interface{}) error {
+	if req == nil {
+		return errors.New("no data")
+	}
+
+	if v, ok := req["path"]; ok && v != nil {
+		if r.Path, ok = v.(string); !ok {
+			return errors.New("path not a string")
+		}
+	}
+
+	r.Strategy = ImportStrategyRename
+	if v, ok := req["strategy"]; ok && v != nil {
+		if r.Strategy, ok = v.(string); !ok {
+			return errors.New("strategy not a string")
+		}
+	}
+
+	switch r.Strategy {
+	case ImportStrategySkip, ImportStrategyOverwrite, ImportStrategyRename:
+	default:
+		return fmt.Errorf("unknown import strategy: %s", r.Strategy)
+	}
+
+	return nil
+}
+
+// Add counts the created workspace item.
+func (s *ImportSummary) Add(t WorkspaceType) {
+	switch t {
+	case WorkspaceTypeFolder:
+		s.Folders++
+	case WorkspaceTypeServer:
+		s.Servers++
+	case WorkspaceTypeQuery:
+		s.Queries++
+	}
+}
 
+// Check checks that the export file can be imported.
+func (e *WorkspaceExport) Check() error {
+	if e.FormatVersion < 1 || e.FormatVersion > ExportFormatVersion {
+		return fmt.Errorf("unsupported export format version: %d", e.FormatVersion)
+	}
 	return nil
 }
+
+// sensitive request metadata keys, the keys ending in -token are sensitive too.
+var exportSensitiveMetadata = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"x-api-key":           {},
+}
+
+// Redact removes or replaces credentials of the workspace item and the sensitive metadata of its saved request.
+func (w *Workspace) Redact(mode string) {
+	if mode == ExportRedactNone {
+		return
+	}
+
+	if query, ok := w.Data.(*WorkspaceItemQuery); ok && query != nil {
+		query.Request.redactMetadata(mode)
+		return
+	}
+
+	server, ok := w.Data.(*WorkspaceItemServer)
+	if !ok || server == nil {
+		return
+	}
+
+	for _, methods := range server.Request {
+		for _, req := range methods {
+			req.redactMetadata(mode)
+		}
+	}
+
+	server.ClientKey = redactSecret(server.ClientKey, mode)
+	server.PKCS12Password = redactSecret(server.PKCS12Password, mode)
+
+	if server.Auth != nil {
+		server.Auth.Password = redactSecret(server.Auth.Password, mode)
+		server.Auth.Token = redactSecret(server.Auth.Token, mode)
+		server.Auth.Secret = redactSecret(server.Auth.Secret, mode)
+		server.Auth.PrivateKey = redactSecret(server.Auth.PrivateKey, mode)
+		server.Auth.GoogleToken = redactSecret(server.Auth.GoogleToken, mode)
+	}
+
+	if server.K8SPortForward != nil && server.K8SPortForward.ClientConfig != nil {
+		server.K8SPortForward.ClientConfig.BearerToken = redactSecret(server.K8SPortForward.ClientConfig.BearerToken, mode)
+	}
+
+	if server.SSHTunnel != nil {
+		server.SSHTunnel.Passphrase = redactSecret(server.SSHTunnel.Passphrase, mode)
+	}
+
+	if server.Proxy != nil {
+		server.Proxy.Password = redactSecret(server.Proxy.Password, mode)
+	}
+}
+
+// redactMetadata removes or replaces the values of the sensitive metadata.
+func (s *SavedQuery) redactMetadata(mode string) {
+	if s == nil {
+		return
+	}
+
+	switch t := s.Metadata.(type) {
+	case []interface{}:
+		for _, item := range t {
+			kv, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if key, _ := kv["key"].(string); isSensitiveMetadata(key) {
+				value, _ := kv["value"].(string)
+				kv["value"] = redactSecret(value, mode)
+			}
+		}
+	case map[string]interface{}:
+		for k, v := range t {
+			if value, ok := v.(string); ok && isSensitiveMetadata(k) {
+				t[k] = redactSecret(value, mode)
+			}
+		}
+	}
+}
+
+func isSensitiveMetadata(key string) bool {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if _, ok := exportSensitiveMetadata[key]; ok {
+		return true
+	}
+	return strings.HasSuffix(key, "-token")
+}
+
+func redactSecret(v, mode string) string {
+	if v == "" || mode == ExportRedactNone {
+		return v
+	}
+	if mode == ExportRedactPlaceholder {
+		return ExportSecretPlaceholder
+	}
+	return ""
+}
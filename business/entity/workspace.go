@@ -2,7 +2,9 @@
 package entity
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/forest33/warthog/pkg/structs"
@@ -14,7 +16,8 @@ const (
 	WorkspaceTypeServer WorkspaceType = "s"
 	WorkspaceTypeQuery  WorkspaceType = "r"
 
-	WorkspaceDuplicatePostfix = "Copy"
+	WorkspaceDuplicatePostfix    = "Copy"
+	WorkspaceBreadcrumbSeparator = "/"
 
 	WorkspaceEventServerUpdated = "server.updated"
 )
@@ -39,6 +42,31 @@ type Workspace struct {
 	UpdatedAt  time.Time     `json:"updated_at"`
 }
 
+// DecodeData converts untyped workspace data, e.g. read from a file, to the workspace item data.
+func (w *Workspace) DecodeData() error {
+	if w.Data == nil {
+		return nil
+	}
+
+	buf, err := json.Marshal(w.Data)
+	if err != nil {
+		return err
+	}
+
+	switch w.Type {
+	case WorkspaceTypeFolder:
+		w.Data = &WorkspaceItemFolder{}
+	case WorkspaceTypeServer:
+		w.Data = &WorkspaceItemServer{}
+	case WorkspaceTypeQuery:
+		w.Data = &WorkspaceItemQuery{}
+	default:
+		return fmt.Errorf("unknown workspace type: %v", w.Type)
+	}
+
+	return json.Unmarshal(buf, w.Data)
+}
+
 // WorkspaceType workspace type.
 type WorkspaceType string
 
@@ -192,6 +220,62 @@ func GetBreadcrumb(w []*Workspace, id int64) []string {
 	return makeBreadcrumb(nodeMap, id, []string{})
 }
 
+// GetSubtree returns the workspace item with all its descendants.
+func GetSubtree(w []*Workspace, id int64) []*Workspace {
+	children := make(map[int64][]*Workspace, len(w))
+	var root *Workspace
+	for _, item := range w {
+		if item.ID == id {
+			root = item
+		}
+		if item.ParentID != nil {
+			children[*item.ParentID] = append(children[*item.ParentID], item)
+		}
+	}
+	if root == nil {
+		return nil
+	}
+
+	items := []*Workspace{root}
+	for i := 0; i < len(items); i++ {
+		items = append(items, children[items[i].ID]...)
+	}
+
+	return items
+}
+
+// FindByBreadcrumb returns workspace items of the given type matching the breadcrumb.
+func FindByBreadcrumb(w []*Workspace, t WorkspaceType, breadcrumb []string) []*Workspace {
+	if len(breadcrumb) == 0 {
+		return nil
+	}
+
+	nodeMap := structs.SliceToMap(w, func(w *Workspace) int64 { return w.ID })
+	found := make([]*Workspace, 0, 1)
+
+	for _, item := range w {
+		if item.Type != t || item.Title != breadcrumb[len(breadcrumb)-1] {
+			continue
+		}
+		b := makeBreadcrumb(nodeMap, item.ID, []string{})
+		if len(b) != len(breadcrumb) {
+			continue
+		}
+		equal := true
+		for i := range b {
+			if b[i] != breadcrumb[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			found = append(found, item)
+		}
+	}
+
+	return found
+}
+
 func makeBreadcrumb(nodeMap map[int64]*Workspace, id int64, breadcrumb []string) []string {
 	if _, ok := nodeMap[id]; !ok {
 		return breadcrumb
@@ -269,6 +353,31 @@ func MakeWorkspaceTree(w []*Workspace, filter *WorkspaceTreeFilter, selectedID i
 	return tree
 }
 
+// FindTreeNode returns the tree node with the given id.
+func FindTreeNode(tree []*WorkspaceTreeNode, id int64) *WorkspaceTreeNode {
+	for _, n := range tree {
+		if n.Data.ID == id {
+			return n
+		}
+		if found := FindTreeNode(n.Nodes, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Flatten returns workspace items of the given type from the subtree in the tree order.
+func (n *WorkspaceTreeNode) Flatten(t WorkspaceType) []*Workspace {
+	items := make([]*Workspace, 0, len(n.Nodes))
+	if n.Data.Type == t {
+		items = append(items, n.Data)
+	}
+	for _, child := range n.Nodes {
+		items = append(items, child.Flatten(t)...)
+	}
+	return items
+}
+
 // WorkspaceState count of folders/servers/queries.
 type WorkspaceState struct {
 	Folders            int    `json:"folders"`
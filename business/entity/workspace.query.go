@@ -2,13 +2,20 @@
 package entity
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strconv"
 )
 
 // SavedQuery saved query.
 type SavedQuery struct {
-	Input    interface{} `json:"input"`
-	Metadata interface{} `json:"metadata"`
+	Input       interface{}    `json:"input"`
+	Metadata    interface{}    `json:"metadata"`
+	Extract     []*ExtractRule `json:"extract,omitempty"`
+	Assertions  []*Assertion   `json:"assertions,omitempty"`
+	Body        string         `json:"body,omitempty"`
+	Compression string         `json:"compression,omitempty"`
 }
 
 // WorkspaceItemQuery stored query data.
@@ -62,7 +69,9 @@ func (s *WorkspaceItemQuery) Model(req map[string]interface{}) error {
 	}
 	if v, ok := req["request"]; ok && v != nil {
 		sq := &SavedQuery{}
-		sq.Model(req["request"].(map[string]interface{}))
+		if err := sq.Model(req["request"].(map[string]interface{})); err != nil {
+			return err
+		}
 		s.Request = sq
 	}
 	if v, ok := req["description"]; ok && v != nil {
@@ -73,9 +82,9 @@ func (s *WorkspaceItemQuery) Model(req map[string]interface{}) error {
 }
 
 // Model creates SavedQuery from UI request.
-func (s *SavedQuery) Model(req map[string]interface{}) {
+func (s *SavedQuery) Model(req map[string]interface{}) error {
 	if req == nil {
-		return
+		return nil
 	}
 
 	if v, ok := req["input"]; ok && v != nil {
@@ -84,4 +93,239 @@ func (s *SavedQuery) Model(req map[string]interface{}) {
 	if v, ok := req["metadata"]; ok && v != nil {
 		s.Metadata = v
 	}
+	if v, ok := req["extract"]; ok && v != nil {
+		extract, err := ExtractRulesModel(v)
+		if err != nil {
+			return err
+		}
+		s.Extract = extract
+	}
+	if v, ok := req["assertions"]; ok && v != nil {
+		assertions, err := AssertionsModel(v)
+		if err != nil {
+			return err
+		}
+		s.Assertions = assertions
+	}
+	if v, ok := req["body"]; ok && v != nil {
+		s.Body, _ = v.(string)
+	}
+	if v, ok := req["compression"]; ok && v != nil {
+		s.Compression, _ = v.(string)
+	}
+
+	return nil
+}
+
+// GetExtract returns saved extraction rules.
+func (s *SavedQuery) GetExtract() []*ExtractRule {
+	if s == nil {
+		return nil
+	}
+	return s.Extract
+}
+
+// GetAssertions returns saved assertions.
+func (s *SavedQuery) GetAssertions() []*Assertion {
+	if s == nil {
+		return nil
+	}
+	return s.Assertions
+}
+
+// GetCompression returns saved compression.
+func (s *SavedQuery) GetCompression() string {
+	if s == nil {
+		return ""
+	}
+	return s.Compression
+}
+
+// GetMetadata returns saved metadata as key/value pairs.
+func (s *SavedQuery) GetMetadata() []string {
+	if s == nil || s.Metadata == nil {
+		return nil
+	}
+
+	var md []string
+
+	switch t := s.Metadata.(type) {
+	case []interface{}:
+		md = make([]string, 0, len(t)*2)
+		for _, item := range t {
+			kv, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, _ := kv["key"].(string)
+			value, _ := kv["value"].(string)
+			if key == "" {
+				continue
+			}
+			md = append(md, key, value)
+		}
+	case map[string]interface{}:
+		md = make([]string, 0, len(t)*2)
+		for k, v := range t {
+			if value, ok := v.(string); ok {
+				md = append(md, k, value)
+			}
+		}
+	}
+
+	return md
+}
+
+// GetInput returns saved input in the format of the request data.
+func (s *SavedQuery) GetInput(fields []*Field) map[string]interface{} {
+	if s == nil || s.Input == nil {
+		return map[string]interface{}{}
+	}
+
+	input, ok := s.Input.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	return savedInputToData(fields, input)
+}
+
+func savedInputToData(fields []*Field, input map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(input))
+
+	for _, f := range fields {
+		v, ok := input[f.FQN]
+		if !ok {
+			continue
+		}
+
+		key := f.FQN
+		if f.ProtoFQN != "" {
+			key = f.ProtoFQN
+		}
+
+		switch {
+		case f.Type != TypeMessage:
+			data[key] = v
+		case f.Map != nil:
+			items, ok := v.(map[string]interface{})
+			if !ok || len(f.Map.Fields) == 0 || f.Map.ProtoValueType != TypeMessage {
+				data[key] = v
+				continue
+			}
+			obj := make(map[string]interface{}, len(items))
+			for k, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					obj[k] = savedInputToData(f.Map.Fields, m)
+				}
+			}
+			data[key] = obj
+		case f.Message != nil:
+			if !f.Repeated {
+				if m, ok := v.(map[string]interface{}); ok {
+					data[key] = savedInputToData(f.Message.Fields, m)
+				}
+				continue
+			}
+			items, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			list := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					list = append(list, savedInputToData(f.Message.Fields, m))
+				}
+			}
+			data[key] = list
+		}
+	}
+
+	return data
+}
+
+// JSONToInput converts the decoded protobuf JSON message into the saved input,
+// the message must be decoded with the original field names and enums as numbers.
+func JSONToInput(fields []*Field, msg map[string]interface{}) map[string]interface{} {
+	input := make(map[string]interface{}, len(msg))
+
+	for _, f := range fields {
+		v, ok := msg[f.Name]
+		if !ok || v == nil {
+			continue
+		}
+
+		switch {
+		case f.Type != TypeMessage:
+			if !f.Repeated {
+				input[f.FQN] = jsonToInputValue(f.Type, v)
+				continue
+			}
+			items, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			list := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				list = append(list, jsonToInputValue(f.Type, item))
+			}
+			input[f.FQN] = list
+		case f.Map != nil:
+			items, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			obj := make(map[string]interface{}, len(items))
+			for k, item := range items {
+				if f.Map.ProtoValueType != TypeMessage {
+					obj[k] = jsonToInputValue(f.Map.ProtoValueType, item)
+				} else if m, ok := item.(map[string]interface{}); ok {
+					obj[k] = JSONToInput(f.Map.Fields, m)
+				}
+			}
+			input[f.FQN] = obj
+		case f.Message != nil:
+			if !f.Repeated {
+				if m, ok := v.(map[string]interface{}); ok {
+					input[f.FQN] = JSONToInput(f.Message.Fields, m)
+				}
+				continue
+			}
+			items, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			list := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					list = append(list, JSONToInput(f.Message.Fields, m))
+				}
+			}
+			input[f.FQN] = list
+		}
+	}
+
+	return input
+}
+
+func jsonToInputValue(fieldType string, v interface{}) interface{} {
+	switch fieldType {
+	case TypeBool:
+		return v
+	case TypeBytes:
+		s, _ := v.(string)
+		if data, err := base64.StdEncoding.DecodeString(s); err == nil {
+			s = string(data)
+		}
+		return map[string]interface{}{"value": s, "file": ""}
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
 }
@@ -6,11 +6,46 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/forest33/warthog/pkg/structs"
 )
 
+// file names of the server credentials in the exported commands and code snippets.
+const (
+	RootCertificateFile   = "ca.pem"
+	ClientCertificateFile = "client.pem"
+	ClientKeyFile         = "client.key"
+	ServiceAccountFile    = "service-account.json"
+)
+
+// server transports.
+const (
+	TransportGRPC         = "grpc"
+	TransportGRPCWeb      = "grpc-web"
+	TransportGRPCWebText  = "grpc-web-text"
+	TransportConnectJSON  = "connect-json"
+	TransportConnectProto = "connect-proto"
+	TransportHTTPJSON     = "http-json"
+)
+
+var transports = map[string]struct{}{
+	TransportGRPC:         {},
+	TransportGRPCWeb:      {},
+	TransportGRPCWebText:  {},
+	TransportConnectJSON:  {},
+	TransportConnectProto: {},
+	TransportHTTPJSON:     {},
+}
+
+// Unix domain socket address schemes.
+const (
+	UnixScheme         = "unix:"
+	UnixAbstractScheme = "unix-abstract:"
+)
+
 // ServerRequest read/create/delete server request.
 type ServerRequest struct {
 	ID       int64  `json:"id"`
@@ -36,18 +71,39 @@ type ServerUpdateRequest struct {
 
 // WorkspaceItemServer stored server data.
 type WorkspaceItemServer struct {
-	Addr              string                            `json:"addr,omitempty"`
-	UseReflection     bool                              `json:"use_reflection,omitempty"`
-	ProtoFiles        []string                          `json:"proto_files,omitempty"`
-	ImportPath        []string                          `json:"import_path,omitempty"`
-	NoTLS             bool                              `json:"no_tls,omitempty"`
-	Insecure          bool                              `json:"insecure,omitempty"`
-	RootCertificate   string                            `json:"root_certificate,omitempty"`
-	ClientCertificate string                            `json:"client_certificate,omitempty"`
-	ClientKey         string                            `json:"client_key,omitempty"`
-	Request           map[string]map[string]*SavedQuery `json:"request"`
-	Auth              *Auth                             `json:"auth"`
-	K8SPortForward    *K8SPortForward                   `json:"k8s"`
+	Addr                  string                            `json:"addr,omitempty"`
+	UseReflection         bool                              `json:"use_reflection,omitempty"`
+	ProtoFiles            []string                          `json:"proto_files,omitempty"`
+	ImportPath            []string                          `json:"import_path,omitempty"`
+	NoTLS                 bool                              `json:"no_tls,omitempty"`
+	Insecure              bool                              `json:"insecure,omitempty"`
+	RootCertificate       string                            `json:"root_certificate,omitempty"`
+	ClientCertificate     string                            `json:"client_certificate,omitempty"`
+	ClientKey             string                            `json:"client_key,omitempty"`
+	UseSystemRoots        bool                              `json:"use_system_roots,omitempty"`
+	RootCertificatePath   string                            `json:"root_certificate_path,omitempty"`
+	ClientCertificatePath string                            `json:"client_certificate_path,omitempty"`
+	ClientKeyPath         string                            `json:"client_key_path,omitempty"`
+	PKCS12Path            string                            `json:"pkcs12_path,omitempty"`
+	PKCS12Password        string                            `json:"pkcs12_password,omitempty"`
+	MinTLSVersion         string                            `json:"min_tls_version,omitempty"`
+	MaxTLSVersion         string                            `json:"max_tls_version,omitempty"`
+	CipherSuites          []string                          `json:"cipher_suites,omitempty"`
+	ALPN                  []string                          `json:"alpn,omitempty"`
+	Transport             string                            `json:"transport,omitempty"`
+	HTTP2                 bool                              `json:"http2,omitempty"`
+	Authority             string                            `json:"authority,omitempty"`
+	ServerName            string                            `json:"server_name,omitempty"`
+	UserAgent             string                            `json:"user_agent,omitempty"`
+	Request               map[string]map[string]*SavedQuery `json:"request"`
+	Auth                  *Auth                             `json:"auth"`
+	K8SPortForward        *K8SPortForward                   `json:"k8s"`
+	SSHTunnel             *SSHTunnel                        `json:"ssh,omitempty"`
+	Proxy                 *Proxy                            `json:"proxy,omitempty"`
+	CallOptions           *CallOptions                      `json:"call_options,omitempty"`
+	ServiceConfig         string                            `json:"service_config,omitempty"`
+	Resolver              *Resolver                         `json:"resolver,omitempty"`
+	EnvironmentID         *int64                            `json:"environment_id,omitempty"`
 }
 
 // Model creates ServerRequest from UI request.
@@ -88,7 +144,9 @@ func (r *ServerUpdateRequest) Model(req map[string]interface{}) error {
 	}
 	if v, ok := req["request"]; ok && v != nil {
 		sq := &SavedQuery{}
-		sq.Model(req["request"].(map[string]interface{}))
+		if err := sq.Model(req["request"].(map[string]interface{})); err != nil {
+			return err
+		}
 		r.Request = sq
 	}
 
@@ -128,27 +186,175 @@ func (s *WorkspaceItemServer) Model(server map[string]interface{}) error {
 	if v, ok := server["client_key"]; ok && v != nil {
 		s.ClientKey = v.(string)
 	}
+	if v, ok := server["use_system_roots"]; ok && v != nil {
+		s.UseSystemRoots = v.(bool)
+	}
+	if v, ok := server["root_certificate_path"]; ok && v != nil {
+		s.RootCertificatePath = v.(string)
+	}
+	if v, ok := server["client_certificate_path"]; ok && v != nil {
+		s.ClientCertificatePath = v.(string)
+	}
+	if v, ok := server["client_key_path"]; ok && v != nil {
+		s.ClientKeyPath = v.(string)
+	}
+	if v, ok := server["pkcs12_path"]; ok && v != nil {
+		s.PKCS12Path = v.(string)
+	}
+	if v, ok := server["pkcs12_password"]; ok && v != nil {
+		s.PKCS12Password = v.(string)
+	}
+	if v, ok := server["min_tls_version"]; ok && v != nil {
+		s.MinTLSVersion = v.(string)
+	}
+	if v, ok := server["max_tls_version"]; ok && v != nil {
+		s.MaxTLSVersion = v.(string)
+	}
+	if v, ok := server["cipher_suites"]; ok && v != nil {
+		s.CipherSuites = structs.Map(v.([]interface{}), func(p interface{}) string { return p.(string) })
+	}
+	if v, ok := server["alpn"]; ok && v != nil {
+		s.ALPN = structs.Map(v.([]interface{}), func(p interface{}) string { return p.(string) })
+	}
+	if err := s.validateTLS(); err != nil {
+		return err
+	}
+	if v, ok := server["transport"]; ok && v != nil {
+		s.Transport = v.(string)
+		if _, ok := transports[s.Transport]; !ok && s.Transport != "" {
+			return fmt.Errorf("unknown transport: %s", s.Transport)
+		}
+	}
+	if v, ok := server["http2"]; ok && v != nil {
+		s.HTTP2 = v.(bool)
+	}
+	if v, ok := server["authority"]; ok && v != nil {
+		s.Authority = v.(string)
+	}
+	if v, ok := server["server_name"]; ok && v != nil {
+		s.ServerName = v.(string)
+	}
+	if v, ok := server["user_agent"]; ok && v != nil {
+		s.UserAgent = v.(string)
+	}
+	if v, ok := server["environment_id"]; ok && v != nil && v.(float64) > 0 {
+		s.EnvironmentID = structs.Ref(int64(v.(float64)))
+	}
 	if v, ok := server["k8s"]; ok && v != nil && len(v.(map[string]interface{})) > 0 {
 		s.K8SPortForward = &K8SPortForward{}
 		if err := s.K8SPortForward.Model(v.(map[string]interface{})); err != nil {
 			return err
 		}
 	}
+	if v, ok := server["ssh"]; ok && v != nil && len(v.(map[string]interface{})) > 0 {
+		s.SSHTunnel = &SSHTunnel{}
+		if err := s.SSHTunnel.Model(v.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
 	if v, ok := server["auth"]; ok && v != nil && len(v.(map[string]interface{})) > 0 {
 		s.Auth = &Auth{}
 		if err := s.Auth.Model(v.(map[string]interface{})); err != nil {
 			return err
 		}
 	}
+	if s.IsUnixSocket() && (s.IsK8SEnabled() || s.IsSSHEnabled()) {
+		return errors.New("port forwarding requires the host:port server address, not a Unix socket")
+	}
+	if v, ok := server["proxy"]; ok && v != nil && len(v.(map[string]interface{})) > 0 {
+		s.Proxy = &Proxy{}
+		if err := s.Proxy.Model(v.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	if v, ok := server["call_options"]; ok && v != nil && len(v.(map[string]interface{})) > 0 {
+		s.CallOptions = &CallOptions{}
+		if err := s.CallOptions.Model(v.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	if v, ok := server["service_config"]; ok && v != nil {
+		s.ServiceConfig = strings.TrimSpace(v.(string))
+		if err := validateServiceConfig(s.ServiceConfig); err != nil {
+			return err
+		}
+	}
+	if v, ok := server["resolver"]; ok && v != nil && len(v.(map[string]interface{})) > 0 {
+		s.Resolver = &Resolver{}
+		if err := s.Resolver.Model(v.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	if s.Resolver.IsEnabled() {
+		switch {
+		case !s.IsNativeTransport():
+			return errors.New("the resolver requires the gRPC transport")
+		case s.IsUnixSocket():
+			return errors.New("the resolver requires the host:port server address, not a Unix socket")
+		case s.IsK8SEnabled() || s.IsSSHEnabled():
+			return errors.New("the resolver can not be used with port forwarding")
+		}
+	}
 
 	return nil
 }
 
+// IsNativeTransport checks whether the server is called over the native gRPC protocol.
+func (s *WorkspaceItemServer) IsNativeTransport() bool {
+	return s.Transport == "" || s.Transport == TransportGRPC
+}
+
+// IsUnixSocket checks whether the server address is a Unix domain socket.
+func (s *WorkspaceItemServer) IsUnixSocket() bool {
+	_, ok := UnixSocketPath(s.Addr)
+	return ok
+}
+
+// UnixSocketPath returns the socket path of the unix: or unix-abstract: address, the abstract socket path starts with @.
+func UnixSocketPath(addr string) (string, bool) {
+	switch {
+	case strings.HasPrefix(addr, UnixAbstractScheme):
+		return "@" + strings.TrimPrefix(addr, UnixAbstractScheme), true
+	case strings.HasPrefix(addr, UnixScheme+"//"):
+		return strings.TrimPrefix(addr, UnixScheme+"//"), true
+	case strings.HasPrefix(addr, UnixScheme):
+		return strings.TrimPrefix(addr, UnixScheme), true
+	}
+	return "", false
+}
+
+// UnixSocketAddr returns the unix: or unix-abstract: address of the socket path.
+func UnixSocketAddr(path string) string {
+	switch {
+	case strings.HasPrefix(path, "@"):
+		return UnixAbstractScheme + strings.TrimPrefix(path, "@")
+	case strings.HasPrefix(path, "/"):
+		return UnixScheme + "//" + path
+	}
+	return UnixScheme + path
+}
+
 // IsK8SEnabled checks whether it is enabled k8s port forwarding.
 func (s *WorkspaceItemServer) IsK8SEnabled() bool {
 	return s.K8SPortForward != nil && s.K8SPortForward.Enabled
 }
 
+// IsSSHEnabled checks whether it is enabled SSH tunnel.
+func (s *WorkspaceItemServer) IsSSHEnabled() bool {
+	return s.SSHTunnel != nil && s.SSHTunnel.Enabled
+}
+
+// SSHTunnelHash calculating SSH tunnel hash.
+func (s *WorkspaceItemServer) SSHTunnelHash() string {
+	data, err := json.Marshal(s.SSHTunnel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
+
 // PortForwardHash calculating port forward hash.
 func (s *WorkspaceItemServer) PortForwardHash() string {
 	data, err := json.Marshal(s.K8SPortForward)
@@ -159,3 +365,39 @@ func (s *WorkspaceItemServer) PortForwardHash() string {
 	hash := md5.Sum(data)
 	return hex.EncodeToString(hash[:])
 }
+
+// ConnectionHash calculating hash of the connection address, authentication, proxy and connection overrides.
+func (s *WorkspaceItemServer) ConnectionHash() (string, error) {
+	data, err := json.Marshal(struct {
+		Addr       string `json:"addr"`
+		Auth       *Auth  `json:"auth"`
+		Proxy      *Proxy `json:"proxy"`
+		Authority  string `json:"authority"`
+		ServerName string `json:"server_name"`
+		UserAgent  string `json:"user_agent"`
+	}{s.Addr, s.Auth, s.Proxy, s.Authority, s.ServerName, s.UserAgent})
+	if err != nil {
+		return "", err
+	}
+
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// CertificateFiles returns the TLS certificates of the server by the exported file names.
+func (s *WorkspaceItemServer) CertificateFiles() map[string]string {
+	if s.NoTLS {
+		return nil
+	}
+
+	files := make(map[string]string, 3)
+	if s.RootCertificate != "" {
+		files[RootCertificateFile] = s.RootCertificate
+	}
+	if s.ClientCertificate != "" && s.ClientKey != "" {
+		files[ClientCertificateFile] = s.ClientCertificate
+		files[ClientKeyFile] = s.ClientKey
+	}
+
+	return files
+}
@@ -0,0 +1,101 @@
+// Package usecase provides business logic.
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/logger"
+)
+
+// EnvironmentUseCase object capable of interacting with EnvironmentUseCase.
+type EnvironmentUseCase struct {
+	ctx             context.Context
+	log             *logger.Zerolog
+	environmentRepo EnvironmentRepo
+}
+
+// NewEnvironmentUseCase creates a new EnvironmentUseCase.
+func NewEnvironmentUseCase(ctx context.Context, log *logger.Zerolog, environmentRepo EnvironmentRepo) *EnvironmentUseCase {
+	return &EnvironmentUseCase{
+		ctx:             ctx,
+		log:             log,
+		environmentRepo: environmentRepo,
+	}
+}
+
+// Get returns all environments.
+func (uc *EnvironmentUseCase) Get() *entity.GUIResponse {
+	return uc.successResponse()
+}
+
+// Create creates environment.
+func (uc *EnvironmentUseCase) Create(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.EnvironmentRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+	if req.Name == "" {
+		return entity.ErrorGUIResponse(errors.New("environment name is empty"))
+	}
+
+	_, err := uc.environmentRepo.Create(&entity.Environment{
+		Name:      req.Name,
+		Variables: req.Variables,
+	})
+	if err != nil {
+		uc.log.Error().Msgf("failed to create environment: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return uc.successResponse()
+}
+
+// Update updates environment.
+func (uc *EnvironmentUseCase) Update(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.EnvironmentRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	_, err := uc.environmentRepo.Update(&entity.Environment{
+		ID:        req.ID,
+		Name:      req.Name,
+		Variables: req.Variables,
+	})
+	if err != nil {
+		uc.log.Error().Msgf("failed to update environment: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return uc.successResponse()
+}
+
+// Delete deletes environment.
+func (uc *EnvironmentUseCase) Delete(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.EnvironmentRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	if err := uc.environmentRepo.Delete(req.ID); err != nil {
+		uc.log.Error().Msgf("failed to delete environment: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return uc.successResponse()
+}
+
+func (uc *EnvironmentUseCase) successResponse() *entity.GUIResponse {
+	env, err := uc.environmentRepo.Get()
+	if err != nil {
+		uc.log.Error().Msgf("failed to get environments: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: env,
+	}
+}
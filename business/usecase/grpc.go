@@ -4,7 +4,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 
@@ -13,6 +15,10 @@ import (
 	"github.com/forest33/warthog/pkg/logger"
 )
 
+const (
+	infoChanCapacity = 100
+)
+
 // GrpcUseCase object capable of interacting with GrpcUseCase.
 type GrpcUseCase struct {
 	ctx                    context.Context
@@ -20,15 +26,32 @@ type GrpcUseCase struct {
 	grpcClient             GrpcClient
 	k8sClient              K8SClient
 	services               []*entity.Service
+	muServices             sync.RWMutex
+	schemas                map[int64]*schemaEntry
+	muSchema               sync.Mutex
+	muProtobuf             sync.Mutex
 	workspaceRepo          WorkspaceRepo
+	environmentRepo        EnvironmentRepo
+	historyRepo            HistoryRepo
 	curServerID            int64
 	curConnectedServerID   int64
-	curService             string
-	curMethod              string
 	curServer              *entity.WorkspaceItemServer
 	curServerClientOptions []grpc.ClientOpt
+	curEnvironment         *entity.Environment
+	curConnectionHash      string
+	curTLSInfo             atomic.Pointer[entity.TLSInfo]
+	lastSessionID          atomic.Uint64
+	querySessions          map[uint64]*querySession
+	muQuerySessions        sync.RWMutex
+	muQuery                sync.Mutex
+	variables              map[string]string
+	muVariables            sync.RWMutex
 	forwardPorts           map[uint16]*forwardPort
 	muForwardPorts         sync.RWMutex
+	sshClient              SSHClient
+	sshTunnels             map[uint16]*sshTunnel
+	muSSHTunnels           sync.RWMutex
+	responseCh             chan *entity.QueryResponse
 	infoCh                 chan *entity.Info
 	errorCh                chan *entity.Error
 }
@@ -42,10 +65,15 @@ type GrpcClient interface {
 	LoadFromProtobuf() ([]*entity.Service, []*entity.ProtobufError, *entity.ProtobufError)
 	LoadFromReflection() ([]*entity.Service, error)
 	GetResponseChannel() chan *entity.QueryResponse
-	GetSentCounter() uint
-	Query(method *entity.Method, data map[string]interface{}, metadata []string) error
-	CancelQuery()
-	CloseStream()
+	GetSentCounter(sessionID uint64) uint
+	GetTLSInfo() *entity.TLSInfo
+	Query(sessionID uint64, method *entity.Method, data map[string]interface{}, metadata []string, compression string) error
+	MessageToJSON(method *entity.Method, data map[string]interface{}) (string, error)
+	JSONToMessage(method *entity.Method, body string) (map[string]interface{}, error)
+	GoSnippet(method *entity.Method, data map[string]interface{}, server *entity.WorkspaceItemServer, metadata []string) (*entity.CodeSnippet, error)
+	CancelQuery(sessionID uint64)
+	CloseStream(sessionID uint64)
+	InvalidateConnections(serverID int64)
 	Close()
 }
 
@@ -54,24 +82,43 @@ type K8SClient interface {
 	PortForward(r *entity.K8SPortForward) (entity.PortForwardControl, error)
 }
 
+// SSHClient is an interface for working with the SSH tunnels.
+type SSHClient interface {
+	Tunnel(t *entity.SSHTunnel) (entity.SSHTunnelControl, error)
+}
+
 type forwardPort struct {
 	control entity.PortForwardControl
 	hash    string
 }
 
+type sshTunnel struct {
+	control  entity.SSHTunnelControl
+	hash     string
+	serverID int64
+}
+
 // NewGrpcUseCase creates a new GrpcUseCase.
-func NewGrpcUseCase(ctx context.Context, log *logger.Zerolog, grpcClient GrpcClient, k8sClient K8SClient, workspaceRepo WorkspaceRepo) *GrpcUseCase {
+func NewGrpcUseCase(ctx context.Context, log *logger.Zerolog, grpcClient GrpcClient, k8sClient K8SClient, sshClient SSHClient, workspaceRepo WorkspaceRepo, environmentRepo EnvironmentRepo, historyRepo HistoryRepo) *GrpcUseCase {
 	useCase := &GrpcUseCase{
-		ctx:           ctx,
-		log:           log,
-		grpcClient:    grpcClient,
-		k8sClient:     k8sClient,
-		workspaceRepo: workspaceRepo,
-		infoCh:        make(chan *entity.Info),
-		errorCh:       make(chan *entity.Error),
+		ctx:             ctx,
+		log:             log,
+		grpcClient:      grpcClient,
+		k8sClient:       k8sClient,
+		sshClient:       sshClient,
+		workspaceRepo:   workspaceRepo,
+		environmentRepo: environmentRepo,
+		historyRepo:     historyRepo,
+		variables:       make(map[string]string),
+		querySessions:   make(map[uint64]*querySession),
+		schemas:         make(map[int64]*schemaEntry),
+		responseCh:      make(chan *entity.QueryResponse),
+		infoCh:          make(chan *entity.Info, infoChanCapacity),
+		errorCh:         make(chan *entity.Error),
 	}
 
 	useCase.initSubscriptions()
+	useCase.responseHandler()
 
 	return useCase
 }
@@ -81,11 +128,17 @@ func (uc *GrpcUseCase) initSubscriptions() {
 		switch e {
 		case entity.WorkspaceEventServerUpdated:
 			w := payload.(*entity.Workspace)
+			uc.muQuery.Lock()
 			if w.ID == uc.curServerID {
 				uc.curServer = w.Data.(*entity.WorkspaceItemServer)
+				uc.curServerClientOptions = clientOptions(uc.curServer)
 				uc.curConnectedServerID = 0
 			}
+			uc.muQuery.Unlock()
+			uc.grpcClient.InvalidateConnections(w.ID)
+			uc.deleteSchema(w.ID)
 			uc.deletePortForward(*w.Data.(*entity.WorkspaceItemServer))
+			uc.deleteServerSSHTunnels(w.ID)
 		default:
 			uc.log.Error().Msgf("unknown workspace event: %s", e.String())
 		}
@@ -100,10 +153,11 @@ func (uc *GrpcUseCase) LoadServer(payload map[string]interface{}) *entity.GUIRes
 	}
 
 	var (
-		query  *entity.Workspace
-		server *entity.Workspace
-		warn   []*entity.ProtobufError
-		err    error
+		query   *entity.Workspace
+		server  *entity.Workspace
+		warn    []*entity.ProtobufError
+		tlsInfo *entity.TLSInfo
+		err     error
 	)
 
 	server, err = uc.workspaceRepo.GetByID(req.ID)
@@ -125,47 +179,32 @@ func (uc *GrpcUseCase) LoadServer(payload map[string]interface{}) *entity.GUIRes
 		}
 	}
 
-	uc.curServerID = server.ID
-	uc.curServer = server.Data.(*entity.WorkspaceItemServer)
-	uc.curServerClientOptions = nil
-	uc.curServerClientOptions = make([]grpc.ClientOpt, 0, 4)
+	curServer := server.Data.(*entity.WorkspaceItemServer)
 
-	if uc.curServer.NoTLS {
-		uc.curServerClientOptions = append(uc.curServerClientOptions, grpc.WithNoTLS())
-	} else {
-		if uc.curServer.Insecure {
-			uc.curServerClientOptions = append(uc.curServerClientOptions, grpc.WithInsecure())
-		}
-		uc.curServerClientOptions = append(uc.curServerClientOptions, grpc.WithRootCertificate(uc.curServer.RootCertificate))
-		if uc.curServer.ClientCertificate != "" && uc.curServer.ClientKey != "" {
-			uc.curServerClientOptions = append(uc.curServerClientOptions,
-				grpc.WithClientCertificate(uc.curServer.ClientCertificate),
-				grpc.WithClientKey(uc.curServer.ClientKey))
-		}
-	}
+	uc.muQuery.Lock()
+	uc.setCurrentServer(server.ID, curServer, clientOptions(curServer))
 
-	if uc.curServer.UseReflection {
-		err = uc.connect(req.ID)
+	if curServer.UseReflection {
+		err = uc.connect(server.ID)
 		if err != nil {
+			uc.muQuery.Unlock()
 			uc.log.Error().Msgf("failed connect to gRPC server: %v", err)
 			return entity.ErrorGUIResponse(err, "server_id", req.ID)
 		}
 
-		uc.addInfoMessage(&entity.Info{Message: entity.MsgServerReflectionInfo})
-
-		uc.services, err = uc.grpcClient.LoadFromReflection()
+		services, err := uc.getReflectionServices(server.ID)
+		uc.muQuery.Unlock()
 		if err != nil {
-			uc.clearInfoMessages()
 			uc.log.Error().Msgf("failed to get services: %v", err)
 			return entity.ErrorGUIResponse(err)
 		}
+		uc.setServices(services)
 
-		uc.clearInfoMessages()
+		tlsInfo = uc.grpcClient.GetTLSInfo()
+		uc.certificateWarnings(tlsInfo)
 	} else {
-		var protoErr *entity.ProtobufError
-		uc.grpcClient.AddProtobuf(uc.curServer.ProtoFiles...)
-		uc.grpcClient.AddImport(uc.curServer.ImportPath...)
-		uc.services, warn, protoErr = uc.grpcClient.LoadFromProtobuf()
+		uc.muQuery.Unlock()
+		services, w, protoErr := uc.getProtobufServices(server.ID, curServer)
 		if protoErr != nil {
 			uc.log.Error().Msgf("failed to get services: %v", protoErr.Err)
 			return &entity.GUIResponse{
@@ -178,8 +217,12 @@ func (uc *GrpcUseCase) LoadServer(payload map[string]interface{}) *entity.GUIRes
 				},
 			}
 		}
+		uc.setServices(services)
+		warn = w
 	}
 
+	uc.resolveQueryBody(query)
+
 	server.Breadcrumb, err = workspaceUseCase.GetBreadcrumb(req.ID)
 	if err != nil {
 		uc.log.Error().Msgf("failed to make breadcrumb: %v", err)
@@ -190,13 +233,79 @@ func (uc *GrpcUseCase) LoadServer(payload map[string]interface{}) *entity.GUIRes
 		Status: entity.GUIResponseStatusOK,
 		Payload: &entity.LoadServerResponse{
 			Server:   server,
-			Services: uc.services,
+			Services: uc.getServices(),
 			Query:    query,
 			Warning:  warn,
+			TLS:      tlsInfo,
 		},
 	}
 }
 
+// setCurrentServer makes the loaded server current, the running sessions keep the connection they were started on,
+// must be called under muQuery.
+func (uc *GrpcUseCase) setCurrentServer(serverID int64, server *entity.WorkspaceItemServer, opts []grpc.ClientOpt) {
+	uc.curServerID = serverID
+	uc.curServer = server
+	uc.curServerClientOptions = opts
+}
+
+// getCurrentServer returns the loaded server.
+func (uc *GrpcUseCase) getCurrentServer() (int64, *entity.WorkspaceItemServer) {
+	uc.muQuery.Lock()
+	defer uc.muQuery.Unlock()
+
+	return uc.curServerID, uc.curServer
+}
+
+// clientOptions returns the gRPC client options of the server.
+func clientOptions(server *entity.WorkspaceItemServer) []grpc.ClientOpt {
+	opts := make([]grpc.ClientOpt, 0, 4)
+
+	if server.NoTLS {
+		opts = append(opts, grpc.WithNoTLS())
+	} else {
+		if server.Insecure {
+			opts = append(opts, grpc.WithInsecure())
+		}
+		opts = append(opts, grpc.WithRootCertificate(server.RootCertificate))
+		if server.ClientCertificate != "" && server.ClientKey != "" {
+			opts = append(opts,
+				grpc.WithClientCertificate(server.ClientCertificate),
+				grpc.WithClientKey(server.ClientKey))
+		}
+		if server.UseSystemRoots {
+			opts = append(opts, grpc.WithSystemRoots())
+		}
+		opts = append(opts,
+			grpc.WithRootCertificatePath(server.RootCertificatePath),
+			grpc.WithClientCertificatePath(server.ClientCertificatePath, server.ClientKeyPath),
+			grpc.WithPKCS12(server.PKCS12Path, server.PKCS12Password),
+			grpc.WithTLSVersions(server.MinTLSVersion, server.MaxTLSVersion),
+			grpc.WithCipherSuites(server.CipherSuites),
+			grpc.WithALPN(server.ALPN))
+	}
+
+	opts = append(opts, grpc.WithTransport(server.Transport))
+	if server.HTTP2 {
+		opts = append(opts, grpc.WithHTTP2())
+	}
+	if server.Authority != "" {
+		opts = append(opts, grpc.WithAuthority(server.Authority))
+	}
+	if server.ServerName != "" {
+		opts = append(opts, grpc.WithServerName(server.ServerName))
+	}
+	if server.UserAgent != "" {
+		opts = append(opts, grpc.WithUserAgent(server.UserAgent))
+	}
+	opts = append(opts,
+		grpc.WithCallOptions(server.CallOptions),
+		grpc.WithServiceConfig(server.ServiceConfig),
+		grpc.WithResolver(server.Resolver))
+
+	return opts
+}
+
 func (uc *GrpcUseCase) connect(serverID int64) error {
 	if uc.curServer.IsK8SEnabled() {
 		createForward := true
@@ -223,7 +332,28 @@ func (uc *GrpcUseCase) connect(serverID int64) error {
 		}
 	}
 
-	if uc.curConnectedServerID == serverID {
+	if uc.curServer.IsSSHEnabled() {
+		if err := uc.openSSHTunnel(serverID); err != nil {
+			return err
+		}
+	}
+
+	env, err := uc.getEnvironment(uc.curServer.EnvironmentID)
+	if err != nil {
+		uc.log.Error().Msgf("failed to get environment: %v", err)
+		return err
+	}
+
+	uc.curEnvironment = env
+
+	server := uc.getVariables().ResolveServer(uc.curServer)
+	hash, err := server.ConnectionHash()
+	if err != nil {
+		uc.log.Error().Msgf("failed to get connection hash: %v", err)
+		return err
+	}
+
+	if uc.curConnectedServerID == serverID && uc.curConnectionHash == hash {
 		return nil
 	}
 
@@ -231,7 +361,9 @@ func (uc *GrpcUseCase) connect(serverID int64) error {
 
 	uc.addInfoMessage(&entity.Info{Message: entity.MsgConnectingServer})
 
-	err := uc.grpcClient.Connect(uc.curServer.Addr, uc.curServer.Auth, uc.curServerClientOptions...)
+	err = uc.grpcClient.Connect(server.Addr, server.Auth, append(slices.Clip(uc.curServerClientOptions),
+		grpc.WithProxy(server.Proxy), grpc.WithStateHandler(uc.getStateHandler(serverID)),
+		grpc.WithPoolKey(serverID, hash))...)
 	if err != nil {
 		uc.clearInfoMessages()
 		uc.log.Error().Msgf("failed connect to gRPC server: %v", err)
@@ -239,16 +371,69 @@ func (uc *GrpcUseCase) connect(serverID int64) error {
 	}
 
 	uc.curConnectedServerID = serverID
+	uc.curConnectionHash = hash
 
 	return nil
 }
 
+// getStateHandler returns the handler sending the connectivity state transitions of the server to the info channel.
+func (uc *GrpcUseCase) getStateHandler(serverID int64) grpc.StateHandler {
+	return func(state *entity.ConnectionState) {
+		state.ServerID = serverID
+		if state.Error != "" {
+			uc.log.Warn().Msgf("connection to server %d: %s", serverID, state)
+		} else {
+			uc.log.Debug().Msgf("connection to server %d: %s", serverID, state)
+		}
+		// the reconnect handling must not wait for the GUI
+		select {
+		case uc.infoCh <- &entity.Info{State: state}:
+		default:
+			uc.log.Warn().Msgf("info channel is full, the state of server %d is not sent", serverID)
+		}
+	}
+}
+
+// certificateWarnings sends the certificate warnings of the new TLS handshake to the info channel.
+func (uc *GrpcUseCase) certificateWarnings(info *entity.TLSInfo) {
+	if info == nil || uc.curTLSInfo.Swap(info) == info {
+		return
+	}
+
+	for _, w := range info.Warnings {
+		uc.log.Warn().Msgf("TLS: %s", w)
+		uc.addInfoMessage(&entity.Info{Message: "TLS: " + w})
+	}
+}
+
+// getEnvironment returns the environment of the server, nil if the server has no environment or it was deleted.
+func (uc *GrpcUseCase) getEnvironment(id *int64) (*entity.Environment, error) {
+	if id == nil {
+		return nil, nil
+	}
+
+	env, err := uc.environmentRepo.GetByID(*id)
+	if errors.Is(err, entity.ErrEnvironmentNotExists) {
+		uc.log.Warn().Msgf("environment %d not exists, variables are not substituted", *id)
+		return nil, nil
+	}
+
+	return env, err
+}
+
+func (uc *GrpcUseCase) getServices() []*entity.Service {
+	uc.muServices.RLock()
+	defer uc.muServices.RUnlock()
+	return uc.services
+}
+
 func (uc *GrpcUseCase) getServiceByName(serviceName string) (*entity.Service, error) {
-	if uc.services == nil {
+	services := uc.getServices()
+	if services == nil {
 		return nil, errors.New("services not initialized")
 	}
 
-	for _, s := range uc.services {
+	for _, s := range services {
 		if s.Name == serviceName {
 			return s, nil
 		}
@@ -272,6 +457,11 @@ func (uc *GrpcUseCase) getMethodByName(serviceName, methodName string) (*entity.
 	return nil, fmt.Errorf("method \"%s.%s\" not found", serviceName, methodName)
 }
 
+// GetResponseChannel returns response channel.
+func (uc *GrpcUseCase) GetResponseChannel() chan *entity.QueryResponse {
+	return uc.responseCh
+}
+
 // GetInfoChannel returns info channel.
 func (uc *GrpcUseCase) GetInfoChannel() chan *entity.Info {
 	return uc.infoCh
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+// ExportGrpcurl returns the current query as the grpcurl command.
+func (uc *GrpcUseCase) ExportGrpcurl(payload map[string]interface{}) *entity.GUIResponse {
+	req, method, server, vars, err := uc.exportQuery(payload)
+	if err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	body, err := uc.grpcClient.MessageToJSON(method, vars.ResolveData(req.Data))
+	if err != nil {
+		uc.log.Error().Msgf("failed to encode request: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+	if body == "{}" {
+		body = ""
+	}
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: entity.NewGrpcurlExport(vars.ResolveServer(server), req.Service, req.Method, body, vars.ResolveMetadata(req.Metadata)),
+	}
+}
+
+// exportQuery returns the query of the loaded server with its method, the server and variables.
+func (uc *GrpcUseCase) exportQuery(payload map[string]interface{}) (*entity.Query, *entity.Method, *entity.WorkspaceItemServer, *entity.Environment, error) {
+	req := &entity.Query{}
+	if err := req.Model(payload); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	serverID, server := uc.getCurrentServer()
+	if server == nil || serverID != req.ServerID {
+		return nil, nil, nil, nil, errors.New("server not loaded")
+	}
+
+	method, err := uc.getMethodByName(req.Service, req.Method)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	env, err := uc.getEnvironment(server.EnvironmentID)
+	if err != nil {
+		uc.log.Error().Msgf("failed to get environment: %v", err)
+		return nil, nil, nil, nil, err
+	}
+
+	return req, method, server, uc.withVariables(env), nil
+}
+
+// resolveQueryBody converts the imported request body of the query into the saved input.
+func (uc *GrpcUseCase) resolveQueryBody(query *entity.Workspace) {
+	if query == nil {
+		return
+	}
+	item, ok := query.Data.(*entity.WorkspaceItemQuery)
+	if !ok || item.Request == nil || item.Request.Body == "" || item.Request.Input != nil {
+		return
+	}
+
+	method, err := uc.getMethodByName(item.Service, item.Method)
+	if err != nil {
+		uc.log.Error().Msgf("failed to convert request body: %v", err)
+		return
+	}
+
+	msg, err := uc.grpcClient.JSONToMessage(method, item.Request.Body)
+	if err != nil {
+		uc.log.Error().Msgf("failed to convert request body: %v", err)
+		return
+	}
+
+	item.Request.Input = entity.JSONToInput(method.Input, msg)
+	item.Request.Body = ""
+
+	if _, err := uc.workspaceRepo.Update(&entity.Workspace{ID: query.ID, Data: item}); err != nil {
+		uc.log.Error().Msgf("failed to update query: %v", err)
+	}
+}
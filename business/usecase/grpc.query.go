@@ -12,51 +12,117 @@ func (uc *GrpcUseCase) Query(payload map[string]interface{}) *entity.GUIResponse
 		return entity.ErrorGUIResponse(err)
 	}
 
-	if err := uc.connect(req.ServerID); err != nil {
-		uc.log.Error().Msgf("failed connect to gRPC server: %v", err)
+	return uc.sendQuery(req)
+}
+
+// RunHistory executes the request of the history entry again.
+func (uc *GrpcUseCase) RunHistory(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.HistoryRequest{}
+	if err := req.Model(payload); err != nil {
 		return entity.ErrorGUIResponse(err)
 	}
 
-	uc.clearInfoMessages()
-
-	method, err := uc.getMethodByName(req.Service, req.Method)
+	h, err := uc.historyRepo.GetByID(req.ID)
 	if err != nil {
+		uc.log.Error().Msgf("failed to get history entry: %v", err)
 		return entity.ErrorGUIResponse(err)
 	}
 
-	uc.initQuery(req)
-	err = uc.grpcClient.Query(method, req.Data, req.Metadata)
-	if errors.Is(err, entity.ErrNotConnected) {
-		uc.curConnectedServerID = 0
-		uc.clearInfoMessages()
-		return uc.Query(payload)
+	// the server of the entry is loaded as the queries are sent to the loaded server
+	if resp := uc.LoadServer(map[string]interface{}{"id": float64(h.ServerID)}); resp.Status != entity.GUIResponseStatusOK {
+		return resp
+	}
+
+	return uc.sendQuery(h.GetQuery())
+}
+
+func (uc *GrpcUseCase) sendQuery(req *entity.Query) *entity.GUIResponse {
+	id, err := uc.query(req, true)
+	if err != nil {
+		return entity.ErrorGUIResponse(err)
 	}
 
 	return &entity.GUIResponse{
 		Status: entity.GUIResponseStatusOK,
 		Payload: &entity.QueryResponse{
-			Sent: uc.grpcClient.GetSentCounter(),
+			SessionID: id,
+			Sent:      uc.grpcClient.GetSentCounter(id),
 		},
 	}
 }
 
-// CancelQuery aborting a running request.
-func (uc *GrpcUseCase) CancelQuery() {
-	uc.grpcClient.CancelQuery()
+// CancelQuery aborting a running request of the session, all requests are aborted if the session is not set.
+func (uc *GrpcUseCase) CancelQuery(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.SessionRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	uc.grpcClient.CancelQuery(req.SessionID)
+
+	return &entity.GUIResponse{Status: entity.GUIResponseStatusOK}
 }
 
-// CloseStream stops a running gRPC stream.
-func (uc *GrpcUseCase) CloseStream() {
-	uc.grpcClient.CloseStream()
+// CloseStream stops a running gRPC stream of the session, all streams are stopped if the session is not set.
+func (uc *GrpcUseCase) CloseStream(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.SessionRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	uc.grpcClient.CloseStream(req.SessionID)
+
+	return &entity.GUIResponse{Status: entity.GUIResponseStatusOK}
 }
 
-func (uc *GrpcUseCase) initQuery(q *entity.Query) {
-	if uc.curServerID == q.ServerID && uc.curService == q.Service && uc.curMethod == q.Method {
-		return
+// query sends the request to the running client stream of the session or starts the new session,
+// returns the session ID.
+func (uc *GrpcUseCase) query(req *entity.Query, record bool) (uint64, error) {
+	uc.muQuery.Lock()
+	defer uc.muQuery.Unlock()
+
+	if s := uc.getQuerySession(req.SessionID); s != nil && s.isClientStream() {
+		// the error is sent to the response channel of the session
+		_ = uc.grpcClient.Query(req.SessionID, s.method, uc.getVariables().ResolveData(req.Data), nil, req.Compression)
+		return req.SessionID, nil
+	}
+
+	return uc.startQuery(req, record)
+}
+
+func (uc *GrpcUseCase) startQuery(req *entity.Query, record bool) (uint64, error) {
+	if uc.curServer == nil {
+		return 0, errors.New("server not loaded")
+	}
+
+	if err := uc.connect(req.ServerID); err != nil {
+		uc.log.Error().Msgf("failed connect to gRPC server: %v", err)
+		return 0, err
+	}
+
+	uc.clearInfoMessages()
+
+	method, err := uc.getMethodByName(req.Service, req.Method)
+	if err != nil {
+		return 0, err
 	}
 
-	uc.curService = q.Service
-	uc.curMethod = q.Method
+	id := uc.lastSessionID.Add(1)
+	uc.addQuerySession(id, &querySession{
+		query:  req,
+		method: method,
+		record: record,
+	})
+
+	vars := uc.getVariables()
+	data := vars.ResolveData(req.Data)
+	metadata := vars.ResolveMetadata(req.Metadata)
+	if err := uc.grpcClient.Query(id, method, data, metadata, req.Compression); errors.Is(err, entity.ErrNotConnected) {
+		uc.deleteQuerySession(id)
+		uc.curConnectedServerID = 0
+		uc.clearInfoMessages()
+		return uc.startQuery(req, record)
+	}
 
-	uc.CancelQuery()
+	return id, nil
 }
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	historyMaxEntries = 1000
+)
+
+// querySession request of the running session and the state of its responses.
+type querySession struct {
+	query       *entity.Query
+	method      *entity.Method
+	record      bool
+	lastMessage interface{}
+	responses   []*entity.QueryResponse
+}
+
+func (s *querySession) isClientStream() bool {
+	return s.method.Type == entity.MethodTypeClientStream || s.method.Type == entity.MethodTypeBidiStream
+}
+
+func (uc *GrpcUseCase) responseHandler() {
+	go func() {
+		for {
+			select {
+			case <-uc.ctx.Done():
+				return
+			case resp, ok := <-uc.grpcClient.GetResponseChannel():
+				if !ok || resp == nil {
+					return
+				}
+				s := uc.getQuerySession(resp.SessionID)
+				if s == nil {
+					uc.log.Debug().Msgf("response of the finished session %d", resp.SessionID)
+					continue
+				}
+				resp.ServerID, resp.Service, resp.Method = s.query.ServerID, s.query.Service, s.query.Method
+				uc.extract(resp, s.query.Extract)
+				if resp.Message != nil {
+					s.lastMessage = resp.Message
+				}
+				s.responses = append(s.responses, resp)
+				resp.Last = uc.assert(resp, s)
+				if resp.Last {
+					uc.deleteQuerySession(resp.SessionID)
+				}
+				select {
+				case uc.responseCh <- resp:
+				case <-uc.ctx.Done():
+					return
+				}
+				if resp.Last {
+					uc.certificateWarnings(uc.grpcClient.GetTLSInfo())
+					uc.addHistory(s)
+				}
+			}
+		}
+	}()
+}
+
+func (uc *GrpcUseCase) addQuerySession(id uint64, s *querySession) {
+	uc.muQuerySessions.Lock()
+	defer uc.muQuerySessions.Unlock()
+	uc.querySessions[id] = s
+}
+
+func (uc *GrpcUseCase) getQuerySession(id uint64) *querySession {
+	uc.muQuerySessions.RLock()
+	defer uc.muQuerySessions.RUnlock()
+	return uc.querySessions[id]
+}
+
+func (uc *GrpcUseCase) deleteQuerySession(id uint64) {
+	uc.muQuerySessions.Lock()
+	defer uc.muQuerySessions.Unlock()
+	delete(uc.querySessions, id)
+}
+
+// assert evaluates the assertions of the session against the last response of the call,
+// the body assertions of the streams are checked against the last received message.
+func (uc *GrpcUseCase) assert(resp *entity.QueryResponse, s *querySession) bool {
+	if !resp.IsLast(s.method.Type) {
+		return false
+	}
+	if len(s.query.Assertions) == 0 {
+		return true
+	}
+
+	last := *resp
+	if last.Message == nil {
+		last.Message = s.lastMessage
+	}
+
+	resp.Assertions = make([]*entity.AssertionResult, 0, len(s.query.Assertions))
+	for _, a := range s.query.Assertions {
+		resp.Assertions = append(resp.Assertions, a.Evaluate(&last))
+	}
+
+	return true
+}
+
+// addHistory records the call made from the GUI into the history.
+func (uc *GrpcUseCase) addHistory(s *querySession) {
+	if !s.record {
+		return
+	}
+
+	q := s.query
+
+	h := &entity.History{
+		ServerID: q.ServerID,
+		Service:  q.Service,
+		Method:   q.Method,
+		Data:     q.Data,
+		Input:    q.Input,
+		Metadata: q.Metadata,
+	}
+	h.SetResponses(s.responses)
+
+	if _, err := uc.historyRepo.Create(h); err != nil {
+		uc.log.Error().Msgf("failed to add history entry: %v", err)
+		return
+	}
+	if err := uc.historyRepo.Truncate(historyMaxEntries); err != nil {
+		uc.log.Error().Msgf("failed to truncate history: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	responseDrainTimeout = time.Second
+)
+
+// RunQuery executes the saved query and waits for the server responses.
+func (uc *GrpcUseCase) RunQuery(queryID int64, timeout time.Duration) ([]*entity.QueryResponse, error) {
+	resp := uc.LoadServer(map[string]interface{}{"id": float64(queryID)})
+	if resp.Status != entity.GUIResponseStatusOK {
+		return nil, resp.Error
+	}
+
+	server := resp.Payload.(*entity.LoadServerResponse)
+	if server.Query == nil {
+		return nil, errors.New("workspace item is not a query")
+	}
+
+	item, ok := server.Query.Data.(*entity.WorkspaceItemQuery)
+	if !ok {
+		return nil, errors.New("wrong query data")
+	}
+
+	method, err := uc.getMethodByName(item.Service, item.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uc.query(&entity.Query{
+		ServerID:    server.Server.ID,
+		Service:     item.Service,
+		Method:      item.Method,
+		Data:        item.Request.GetInput(method.Input),
+		Metadata:    item.Request.GetMetadata(),
+		Extract:     item.Request.GetExtract(),
+		Assertions:  item.Request.GetAssertions(),
+		Compression: item.Request.GetCompression(),
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.Type == entity.MethodTypeClientStream || method.Type == entity.MethodTypeBidiStream {
+		uc.grpcClient.CloseStream(id)
+	}
+
+	return uc.waitResponses(id, timeout)
+}
+
+// waitResponses waits for the responses of the session until the last one.
+func (uc *GrpcUseCase) waitResponses(sessionID uint64, timeout time.Duration) ([]*entity.QueryResponse, error) {
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	responses := make([]*entity.QueryResponse, 0, 1)
+
+	for {
+		select {
+		case <-uc.ctx.Done():
+			return responses, uc.ctx.Err()
+		case <-timeoutCh:
+			uc.grpcClient.CancelQuery(sessionID)
+			uc.drainResponses(sessionID)
+			return responses, entity.ErrQueryTimeout
+		case r := <-uc.responseCh:
+			if r.SessionID != sessionID {
+				continue
+			}
+			responses = append(responses, r)
+			if r.Last {
+				return responses, nil
+			}
+		}
+	}
+}
+
+// RunCollection executes all saved queries of the folder or server subtree in the workspace order.
+func (uc *GrpcUseCase) RunCollection(id int64, timeout time.Duration) (*entity.RunReport, error) {
+	w, err := uc.workspaceRepo.Get()
+	if err != nil {
+		uc.log.Error().Msgf("failed to get workspace: %v", err)
+		return nil, err
+	}
+
+	node := entity.FindTreeNode(entity.MakeWorkspaceTree(w, nil, 0), id)
+	if node == nil {
+		return nil, entity.ErrWorkspaceNotExists
+	}
+	if node.Data.Type != entity.WorkspaceTypeFolder && node.Data.Type != entity.WorkspaceTypeServer {
+		return nil, fmt.Errorf("workspace item %d is not a folder or server", id)
+	}
+
+	report := &entity.RunReport{
+		Name:      strings.Join(entity.GetBreadcrumb(w, id), entity.WorkspaceBreadcrumbSeparator),
+		StartedAt: time.Now(),
+		Results:   make([]*entity.RunResult, 0, len(node.Nodes)),
+	}
+
+	for _, q := range node.Flatten(entity.WorkspaceTypeQuery) {
+		res := &entity.RunResult{
+			QueryID:    q.ID,
+			Title:      q.Title,
+			Breadcrumb: entity.GetBreadcrumb(w, q.ID),
+		}
+		if item, ok := q.Data.(*entity.WorkspaceItemQuery); ok {
+			res.Service = item.Service
+			res.Method = item.Method
+		}
+
+		res.SetResponses(uc.RunQuery(q.ID, timeout))
+		report.AddResult(res)
+	}
+
+	report.Duration = time.Since(report.StartedAt).String()
+
+	return report, nil
+}
+
+// drainResponses waits for the last response of the canceled session, so the session is finished before the next query.
+func (uc *GrpcUseCase) drainResponses(sessionID uint64) {
+	timer := time.NewTimer(responseDrainTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return
+		case r := <-uc.responseCh:
+			if r.SessionID == sessionID && r.Last {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	schemaReflectionTTL = 5 * time.Minute
+)
+
+var errSchemaNotConnected = errors.New("server is not connected")
+
+// schemaEntry cached services of the server, the protobuf services are loaded again when the files have changed,
+// the reflection services when the TTL has expired.
+type schemaEntry struct {
+	services   []*entity.Service
+	warnings   []*entity.ProtobufError
+	source     string
+	hash       string
+	loadedAt   time.Time
+	refreshing bool
+}
+
+type schemaLoader func() ([]*entity.Service, []*entity.ProtobufError, error)
+
+// getProtobufServices returns the cached services of the server, the services are parsed if there are none
+// and parsed again in the background if the protobuf files have changed.
+func (uc *GrpcUseCase) getProtobufServices(serverID int64, server *entity.WorkspaceItemServer) ([]*entity.Service, []*entity.ProtobufError, *entity.ProtobufError) {
+	load := func() ([]*entity.Service, []*entity.ProtobufError, *entity.ProtobufError) {
+		uc.muProtobuf.Lock()
+		defer uc.muProtobuf.Unlock()
+		uc.grpcClient.AddProtobuf(server.ProtoFiles...)
+		uc.grpcClient.AddImport(server.ImportPath...)
+		return uc.grpcClient.LoadFromProtobuf()
+	}
+
+	source, err := server.ProtobufHash()
+	if err != nil {
+		uc.log.Debug().Msgf("failed to get protobuf hash of server %d: %v", serverID, err)
+		return load()
+	}
+
+	if e := uc.getSchema(serverID); e != nil {
+		if e.source != source {
+			uc.refreshSchema(serverID, source, func() ([]*entity.Service, []*entity.ProtobufError, error) {
+				services, warn, protoErr := load()
+				if protoErr != nil {
+					return nil, nil, protoErr
+				}
+				return services, warn, nil
+			})
+		}
+		return e.services, e.warnings, nil
+	}
+
+	services, warn, protoErr := load()
+	if protoErr == nil {
+		uc.setSchema(serverID, source, services, warn)
+	}
+
+	return services, warn, protoErr
+}
+
+// getReflectionServices returns the cached services of the connection, the services are loaded by the reflection
+// if there are none and loaded again in the background when the TTL has expired, the server must be connected.
+func (uc *GrpcUseCase) getReflectionServices(serverID int64) ([]*entity.Service, error) {
+	source := uc.curConnectionHash
+
+	if e := uc.getSchema(serverID); e != nil && e.source == source {
+		if time.Since(e.loadedAt) > schemaReflectionTTL {
+			uc.refreshSchema(serverID, source, func() ([]*entity.Service, []*entity.ProtobufError, error) {
+				uc.muQuery.Lock()
+				defer uc.muQuery.Unlock()
+				if uc.curConnectedServerID != serverID || uc.curConnectionHash != source {
+					return nil, nil, errSchemaNotConnected
+				}
+				services, err := uc.grpcClient.LoadFromReflection()
+				return services, nil, err
+			})
+		}
+		return e.services, nil
+	}
+
+	uc.addInfoMessage(&entity.Info{Message: entity.MsgServerReflectionInfo})
+	defer uc.clearInfoMessages()
+
+	services, err := uc.grpcClient.LoadFromReflection()
+	if err != nil {
+		return nil, err
+	}
+
+	uc.setSchema(serverID, source, services, nil)
+
+	return services, nil
+}
+
+// refreshSchema loads the services of the server in the background, the GUI is notified if the schema has changed.
+func (uc *GrpcUseCase) refreshSchema(serverID int64, source string, load schemaLoader) {
+	uc.muSchema.Lock()
+	e, ok := uc.schemas[serverID]
+	if !ok || e.refreshing {
+		uc.muSchema.Unlock()
+		return
+	}
+	e.refreshing = true
+	uc.muSchema.Unlock()
+
+	go func() {
+		services, warn, err := load()
+
+		uc.muSchema.Lock()
+		e.refreshing = false
+		if cur, ok := uc.schemas[serverID]; !ok || cur != e {
+			uc.muSchema.Unlock()
+			return
+		}
+		uc.muSchema.Unlock()
+
+		if errors.Is(err, errSchemaNotConnected) {
+			return
+		} else if err != nil {
+			uc.log.Warn().Msgf("failed to refresh services of server %d: %v", serverID, err)
+			return
+		}
+
+		if hash := uc.setSchema(serverID, source, services, warn); hash == e.hash {
+			return
+		}
+
+		uc.log.Info().Msgf("services of server %d have changed", serverID)
+		if curServerID, _ := uc.getCurrentServer(); curServerID == serverID {
+			uc.setServices(services)
+		}
+		uc.addInfoMessage(&entity.Info{Schema: &entity.SchemaChanged{ServerID: serverID}})
+	}()
+}
+
+func (uc *GrpcUseCase) getSchema(serverID int64) *schemaEntry {
+	uc.muSchema.Lock()
+	defer uc.muSchema.Unlock()
+	return uc.schemas[serverID]
+}
+
+// setSchema caches the services of the server, returns the schema hash.
+func (uc *GrpcUseCase) setSchema(serverID int64, source string, services []*entity.Service, warn []*entity.ProtobufError) string {
+	hash, err := entity.SchemaHash(services)
+	if err != nil {
+		uc.log.Error().Msgf("failed to get schema hash: %v", err)
+	}
+
+	uc.muSchema.Lock()
+	defer uc.muSchema.Unlock()
+
+	uc.schemas[serverID] = &schemaEntry{
+		services: services,
+		warnings: warn,
+		source:   source,
+		hash:     hash,
+		loadedAt: time.Now(),
+	}
+
+	return hash
+}
+
+func (uc *GrpcUseCase) deleteSchema(serverID int64) {
+	uc.muSchema.Lock()
+	defer uc.muSchema.Unlock()
+	delete(uc.schemas, serverID)
+}
+
+func (uc *GrpcUseCase) setServices(services []*entity.Service) {
+	uc.muServices.Lock()
+	defer uc.muServices.Unlock()
+	uc.services = services
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"github.com/forest33/warthog/business/entity"
+)
+
+// ExportGoSnippet returns the Go client code of the current query.
+func (uc *GrpcUseCase) ExportGoSnippet(payload map[string]interface{}) *entity.GUIResponse {
+	req, method, server, vars, err := uc.exportQuery(payload)
+	if err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	snippet, err := uc.grpcClient.GoSnippet(method, vars.ResolveData(req.Data), vars.ResolveServer(server), vars.ResolveMetadata(req.Metadata))
+	if err != nil {
+		uc.log.Error().Msgf("failed to generate Go code: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: snippet,
+	}
+}
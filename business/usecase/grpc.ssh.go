@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"github.com/forest33/warthog/business/entity"
+)
+
+func (uc *GrpcUseCase) openSSHTunnel(serverID int64) error {
+	hash := uc.curServer.SSHTunnelHash()
+	if existsTunnel := uc.getSSHTunnel(uc.curServer.SSHTunnel.LocalPort); existsTunnel != nil {
+		if existsTunnel.hash == hash {
+			return nil
+		}
+		uc.deleteSSHTunnel(uc.curServer.SSHTunnel.LocalPort, existsTunnel.hash)
+	}
+
+	uc.curConnectedServerID = 0
+	uc.addInfoMessage(&entity.Info{Message: entity.MsgCreatingSSHTunnel})
+
+	tunnel := *uc.curServer.SSHTunnel
+	tunnel.ErrHandler = uc.getSSHTunnelErrorHandler(tunnel.LocalPort, hash, serverID)
+
+	control, err := uc.sshClient.Tunnel(&tunnel)
+	if err != nil {
+		uc.clearInfoMessages()
+		uc.log.Error().Msgf("failed to create SSH tunnel: %v", err)
+		return err
+	}
+
+	uc.addSSHTunnel(serverID, tunnel.LocalPort, hash, control)
+
+	return nil
+}
+
+func (uc *GrpcUseCase) getSSHTunnelErrorHandler(port uint16, hash string, serverID int64) func(err error) {
+	return func(err error) {
+		uc.log.Error().Msgf("SSH tunnel error: %v", err)
+		uc.deleteSSHTunnel(port, hash)
+		if uc.curConnectedServerID == serverID {
+			uc.curConnectedServerID = 0
+			uc.errorCh <- &entity.Error{
+				Message: "SSH tunnel: " + err.Error(),
+			}
+		}
+	}
+}
+
+func (uc *GrpcUseCase) getSSHTunnel(port uint16) *sshTunnel {
+	uc.muSSHTunnels.RLock()
+	defer uc.muSSHTunnels.RUnlock()
+
+	return uc.sshTunnels[port]
+}
+
+func (uc *GrpcUseCase) addSSHTunnel(serverID int64, port uint16, hash string, control entity.SSHTunnelControl) {
+	uc.muSSHTunnels.Lock()
+	defer uc.muSSHTunnels.Unlock()
+
+	if uc.sshTunnels == nil {
+		uc.sshTunnels = make(map[uint16]*sshTunnel, 10)
+	}
+
+	uc.sshTunnels[port] = &sshTunnel{
+		control:  control,
+		hash:     hash,
+		serverID: serverID,
+	}
+}
+
+// deleteSSHTunnel closes the tunnel on the local port if it has the same hash.
+func (uc *GrpcUseCase) deleteSSHTunnel(port uint16, hash string) {
+	uc.muSSHTunnels.Lock()
+	defer uc.muSSHTunnels.Unlock()
+
+	if t, ok := uc.sshTunnels[port]; ok && t.hash == hash {
+		t.control.Close()
+		delete(uc.sshTunnels, port)
+	}
+}
+
+// deleteServerSSHTunnels closes the tunnels opened for the server.
+func (uc *GrpcUseCase) deleteServerSSHTunnels(serverID int64) {
+	uc.muSSHTunnels.Lock()
+	defer uc.muSSHTunnels.Unlock()
+
+	for port, t := range uc.sshTunnels {
+		if t.serverID == serverID {
+			t.control.Close()
+			delete(uc.sshTunnels, port)
+		}
+	}
+}
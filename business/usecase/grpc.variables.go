@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"github.com/forest33/warthog/business/entity"
+)
+
+// GetVariables returns variables extracted from the responses.
+func (uc *GrpcUseCase) GetVariables() *entity.GUIResponse {
+	uc.muVariables.RLock()
+	defer uc.muVariables.RUnlock()
+
+	vars := make(map[string]string, len(uc.variables))
+	for k, v := range uc.variables {
+		vars[k] = v
+	}
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: vars,
+	}
+}
+
+// ClearVariables removes variables extracted from the responses.
+func (uc *GrpcUseCase) ClearVariables() *entity.GUIResponse {
+	uc.muVariables.Lock()
+	uc.variables = make(map[string]string)
+	uc.muVariables.Unlock()
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: map[string]string{},
+	}
+}
+
+// extract stores the response values into the variables according to the extraction rules of the session.
+func (uc *GrpcUseCase) extract(resp *entity.QueryResponse, rules []*entity.ExtractRule) {
+	if resp == nil || resp.Error != nil {
+		return
+	}
+
+	uc.muVariables.Lock()
+	defer uc.muVariables.Unlock()
+
+	for _, r := range rules {
+		if r.Source == entity.ExtractSourceBody && resp.Message == nil {
+			continue
+		}
+		v, err := r.Extract(resp)
+		if err != nil {
+			uc.log.Debug().Msgf("failed to extract variable %s: %v", r.Variable, err)
+			if resp.ExtractErr == "" {
+				resp.ExtractErr = err.Error()
+			}
+			continue
+		}
+		if resp.Variables == nil {
+			resp.Variables = make(map[string]string, len(rules))
+		}
+		resp.Variables[r.Variable] = v
+		uc.variables[r.Variable] = v
+	}
+}
+
+// getVariables returns the current environment merged with the extracted variables.
+func (uc *GrpcUseCase) getVariables() *entity.Environment {
+	return uc.withVariables(uc.curEnvironment)
+}
+
+// withVariables returns the environment merged with the extracted variables.
+func (uc *GrpcUseCase) withVariables(env *entity.Environment) *entity.Environment {
+	uc.muVariables.RLock()
+	defer uc.muVariables.RUnlock()
+
+	if len(uc.variables) == 0 {
+		return env
+	}
+
+	vars := &entity.Environment{Variables: make(map[string]string, len(uc.variables))}
+	if env != nil {
+		vars.ID = env.ID
+		vars.Name = env.Name
+		for k, v := range env.Variables {
+			vars.Variables[k] = v
+		}
+	}
+	for k, v := range uc.variables {
+		vars.Variables[k] = v
+	}
+
+	return vars
+}
@@ -0,0 +1,104 @@
+// Package usecase provides business logic.
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/logger"
+)
+
+// HistoryUseCase object capable of interacting with HistoryUseCase.
+type HistoryUseCase struct {
+	ctx         context.Context
+	log         *logger.Zerolog
+	historyRepo HistoryRepo
+}
+
+// NewHistoryUseCase creates a new HistoryUseCase.
+func NewHistoryUseCase(ctx context.Context, log *logger.Zerolog, historyRepo HistoryRepo) *HistoryUseCase {
+	return &HistoryUseCase{
+		ctx:         ctx,
+		log:         log,
+		historyRepo: historyRepo,
+	}
+}
+
+// Get returns history entries matching the filter.
+func (uc *HistoryUseCase) Get(payload map[string]interface{}) *entity.GUIResponse {
+	filter := &entity.HistoryFilter{}
+	if err := filter.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	history, err := uc.historyRepo.Get(filter)
+	if err != nil {
+		uc.log.Error().Msgf("failed to get history: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: history,
+	}
+}
+
+// Delete deletes history entry.
+func (uc *HistoryUseCase) Delete(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.HistoryRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	if err := uc.historyRepo.Delete(req.ID); err != nil {
+		uc.log.Error().Msgf("failed to delete history entry: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return &entity.GUIResponse{Status: entity.GUIResponseStatusOK}
+}
+
+// Clear deletes history entries of the server, or all entries if the server is not set.
+func (uc *HistoryUseCase) Clear(payload map[string]interface{}) *entity.GUIResponse {
+	filter := &entity.HistoryFilter{}
+	if err := filter.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	if err := uc.historyRepo.Clear(filter.ServerID); err != nil {
+		uc.log.Error().Msgf("failed to clear history: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return &entity.GUIResponse{Status: entity.GUIResponseStatusOK}
+}
+
+// SaveAsQuery creates a saved query from the history entry.
+func (uc *HistoryUseCase) SaveAsQuery(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.HistoryRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	h, err := uc.historyRepo.GetByID(req.ID)
+	if err != nil {
+		uc.log.Error().Msgf("failed to get history entry: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+	if h.ServerTitle == "" {
+		return entity.ErrorGUIResponse(errors.New("server of the history entry no longer exists"))
+	}
+
+	title := req.Title
+	if title == "" {
+		title = h.Method
+	}
+
+	return workspaceUseCase.CreateQuery(h.ServerID, title, &entity.WorkspaceItemQuery{
+		Service:     h.Service,
+		Method:      h.Method,
+		Description: req.Description,
+		Request:     h.GetSavedQuery(),
+	})
+}
@@ -18,6 +18,25 @@ type WorkspaceRepo interface {
 	Delete(id int64) error
 }
 
+// EnvironmentRepo is the common interface implemented EnvironmentRepository methods.
+type EnvironmentRepo interface {
+	Get() ([]*entity.Environment, error)
+	GetByID(id int64) (*entity.Environment, error)
+	Create(in *entity.Environment) (*entity.Environment, error)
+	Update(in *entity.Environment) (*entity.Environment, error)
+	Delete(id int64) error
+}
+
+// HistoryRepo is the common interface implemented HistoryRepository methods.
+type HistoryRepo interface {
+	Get(filter *entity.HistoryFilter) ([]*entity.History, error)
+	GetByID(id int64) (*entity.History, error)
+	Create(in *entity.History) (*entity.History, error)
+	Delete(id int64) error
+	Clear(serverID int64) error
+	Truncate(keep int) error
+}
+
 // SetWorkspaceUseCase sets WorkspaceUseCase instance.
 func SetWorkspaceUseCase(uc *WorkspaceUseCase) {
 	workspaceUseCase = uc
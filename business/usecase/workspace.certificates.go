@@ -0,0 +1,118 @@
+// Package usecase provides business logic.
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/forest33/warthog/business/entity"
+	"github.com/forest33/warthog/pkg/certgen"
+)
+
+// GenerateCertificates creates the development CA with the server and client certificates,
+// saves them to the directory and sets the CA and the client certificate of the server if it is set.
+func (uc *WorkspaceUseCase) GenerateCertificates(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.CertificatesRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	bundle, err := certgen.Generate(&certgen.Options{
+		KeyType:  req.KeyType,
+		Hosts:    req.Hosts,
+		Validity: time.Duration(req.ValidityDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		uc.log.Error().Msgf("failed to generate certificates: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	files, err := saveCertificates(req.Dir, map[string][]byte{
+		entity.RootCertificateFile:   bundle.CA.Cert,
+		entity.CAKeyFile:             bundle.CA.Key,
+		entity.ServerCertificateFile: bundle.Server.Cert,
+		entity.ServerKeyFile:         bundle.Server.Key,
+		entity.ClientCertificateFile: bundle.Client.Cert,
+		entity.ClientKeyFile:         bundle.Client.Key,
+	})
+	if err != nil {
+		uc.log.Error().Msgf("failed to save certificates: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	resp := &entity.CertificatesResponse{
+		Files:             files,
+		RootCertificate:   string(bundle.CA.Cert),
+		ClientCertificate: string(bundle.Client.Cert),
+		ClientKey:         string(bundle.Client.Key),
+	}
+
+	if req.ServerID > 0 {
+		if resp.Server, err = uc.setServerCertificates(req.ServerID, resp); err != nil {
+			uc.log.Error().Msgf("failed to update server: %v", err)
+			return entity.ErrorGUIResponse(err)
+		}
+	}
+
+	return &entity.GUIResponse{
+		Status:  entity.GUIResponseStatusOK,
+		Payload: resp,
+	}
+}
+
+func (uc *WorkspaceUseCase) setServerCertificates(id int64, certs *entity.CertificatesResponse) (*entity.Workspace, error) {
+	server, err := uc.workspaceRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if server.Type != entity.WorkspaceTypeServer {
+		return nil, fmt.Errorf("workspace item %d is not a server", id)
+	}
+
+	data := server.Data.(*entity.WorkspaceItemServer)
+	data.SetCertificates(certs.RootCertificate, certs.ClientCertificate, certs.ClientKey)
+
+	server, err = uc.workspaceRepo.Update(&entity.Workspace{
+		ID:   server.ID,
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.Publish(entity.WorkspaceEventServerUpdated, server)
+
+	return server, nil
+}
+
+// saveCertificates writes the files to the directory, the existing files are not overwritten.
+func saveCertificates(dir string, files map[string][]byte) (map[string]string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]string, len(files))
+	for name := range files {
+		paths[name] = filepath.Join(dir, name)
+		if _, err := os.Stat(paths[name]); err == nil {
+			return nil, fmt.Errorf("file %s already exists", paths[name])
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	for name, data := range files {
+		perm := os.FileMode(0o644)
+		if filepath.Ext(name) == ".key" {
+			perm = 0o600
+		}
+		if err := os.WriteFile(paths[name], data, perm); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
@@ -1,9 +1,13 @@
 package usecase
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/forest33/warthog/business/entity"
 )
@@ -15,13 +19,33 @@ func (uc *WorkspaceUseCase) ExportFile(payload map[string]interface{}) *entity.G
 		return entity.ErrorGUIResponse(err)
 	}
 
-	w, err := uc.workspaceRepo.Get(nil)
+	w, err := uc.workspaceRepo.Get()
 	if err != nil {
 		uc.log.Error().Msgf("failed to get workspace: %v", err)
 		return entity.ErrorGUIResponse(err)
 	}
 
-	data, err := json.Marshal(w)
+	if req.ID != nil {
+		if w = entity.GetSubtree(w, *req.ID); w == nil {
+			return entity.ErrorGUIResponse(entity.ErrWorkspaceNotExists)
+		}
+		if w[0].Type == entity.WorkspaceTypeQuery {
+			return entity.ErrorGUIResponse(errors.New("only a folder or server can be exported"))
+		}
+		w[0].ParentID = nil
+	}
+
+	for _, item := range w {
+		item.Redact(req.Redact)
+	}
+
+	data, err := json.MarshalIndent(&entity.WorkspaceExport{
+		FormatVersion: entity.ExportFormatVersion,
+		AppVersion:    uc.appVersion,
+		ExportedAt:    time.Now(),
+		Redact:        req.Redact,
+		Items:         w,
+	}, "", "  ")
 	if err != nil {
 		uc.log.Error().Msgf("failed to marshal data: %v", err)
 		return entity.ErrorGUIResponse(err)
@@ -40,7 +64,7 @@ func (uc *WorkspaceUseCase) ExportFile(payload map[string]interface{}) *entity.G
 
 // ImportFile imports the workspace from a file.
 func (uc *WorkspaceUseCase) ImportFile(payload map[string]interface{}) *entity.GUIResponse {
-	req := &entity.ExportRequest{}
+	req := &entity.ImportRequest{}
 	if err := req.Model(payload); err != nil {
 		return entity.ErrorGUIResponse(err)
 	}
@@ -51,21 +75,229 @@ func (uc *WorkspaceUseCase) ImportFile(payload map[string]interface{}) *entity.G
 		return entity.ErrorGUIResponse(errors.New("Failed to read file: " + err.Error()))
 	}
 
-	var importData []*entity.Workspace
-	if err := json.Unmarshal(data, &importData); err != nil {
+	importData, err := unmarshalExport(data)
+	if err != nil {
 		uc.log.Error().Msgf("failed to unmarshal file: %v", err)
 		return entity.ErrorGUIResponse(errors.New("Failed to unmarshal file: " + err.Error()))
 	}
 
-	w, err := uc.workspaceRepo.Get(nil)
+	summary, err := uc.importWorkspace(importData.Items, req.Strategy)
 	if err != nil {
-		uc.log.Error().Msgf("failed to get workspace: %v", err)
+		uc.log.Error().Msgf("failed to import workspace: %v", err)
 		return entity.ErrorGUIResponse(err)
 	}
 
-	_ = w
+	w, err := uc.workspaceRepo.Get()
+	if err != nil {
+		uc.log.Error().Msgf("failed to get workspace: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
 
 	return &entity.GUIResponse{
 		Status: entity.GUIResponseStatusOK,
+		Payload: &entity.ImportResponse{
+			Summary: summary,
+			Tree:    entity.MakeWorkspaceTree(w, nil, 0),
+		},
+	}
+}
+
+// unmarshalExport parses the export file, files without the envelope are made by the previous versions.
+func unmarshalExport(data []byte) (*entity.WorkspaceExport, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		items := make([]*entity.Workspace, 0)
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		return &entity.WorkspaceExport{FormatVersion: entity.ExportFormatVersion, Items: items}, nil
+	}
+
+	export := &entity.WorkspaceExport{}
+	if err := json.Unmarshal(data, export); err != nil {
+		return nil, err
+	}
+	if err := export.Check(); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+type workspaceImport struct {
+	strategy    string
+	existing    []*entity.Workspace
+	children    map[int64][]*entity.Workspace
+	summary     *entity.ImportSummary
+	created     []int64
+	overwritten []*entity.Workspace
+}
+
+func (uc *WorkspaceUseCase) importWorkspace(items []*entity.Workspace, strategy string) (*entity.ImportSummary, error) {
+	existing, err := uc.workspaceRepo.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &workspaceImport{
+		strategy: strategy,
+		existing: existing,
+		children: make(map[int64][]*entity.Workspace, len(items)),
+		summary:  &entity.ImportSummary{},
+	}
+
+	ids := make(map[int64]struct{}, len(items))
+	for _, item := range items {
+		if err := item.DecodeData(); err != nil {
+			return nil, err
+		}
+		ids[item.ID] = struct{}{}
+	}
+
+	roots := make([]*entity.Workspace, 0, len(items))
+	for _, item := range items {
+		if getParentID(item.ParentID) == 0 {
+			roots = append(roots, item)
+			continue
+		}
+		if _, ok := ids[*item.ParentID]; !ok {
+			return nil, fmt.Errorf("parent of workspace item \"%s\" not found", item.Title)
+		}
+		imp.children[*item.ParentID] = append(imp.children[*item.ParentID], item)
+	}
+
+	if err := uc.importItems(imp, roots, nil); err != nil {
+		uc.undoImport(imp)
+		return nil, err
+	}
+
+	return imp.summary, nil
+}
+
+// undoImport restores the overwritten items and deletes the created ones, the children are deleted before the parents.
+func (uc *WorkspaceUseCase) undoImport(imp *workspaceImport) {
+	for _, w := range imp.overwritten {
+		if _, err := uc.workspaceRepo.Update(&entity.Workspace{ID: w.ID, Data: w.Data}); err != nil {
+			uc.log.Error().Msgf("failed to restore workspace item %d: %v", w.ID, err)
+			continue
+		}
+		if w.Type == entity.WorkspaceTypeServer {
+			uc.Publish(entity.WorkspaceEventServerUpdated, w)
+		}
+	}
+
+	for i := len(imp.created) - 1; i >= 0; i-- {
+		if err := uc.workspaceRepo.Delete(imp.created[i]); err != nil {
+			uc.log.Error().Msgf("failed to delete workspace item %d: %v", imp.created[i], err)
+		}
+	}
+}
+
+func (uc *WorkspaceUseCase) importItems(imp *workspaceImport, items []*entity.Workspace, parentID *int64) error {
+	sort.SliceStable(items, func(i, j int) bool {
+		return getSort(items[i]) < getSort(items[j])
+	})
+
+	for _, item := range items {
+		id, err := uc.importItem(imp, item, parentID)
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			continue
+		}
+		if err := uc.importItems(imp, imp.children[item.ID], id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *WorkspaceUseCase) importItem(imp *workspaceImport, item *entity.Workspace, parentID *int64) (*int64, error) {
+	exists := imp.findByTitle(item.Type, item.Title, parentID)
+	title := item.Title
+
+	if exists != nil {
+		switch {
+		case item.Type == entity.WorkspaceTypeFolder:
+			imp.summary.Merged++
+			return &exists.ID, nil
+		case imp.strategy == entity.ImportStrategySkip:
+			// the queries of a skipped server are skipped too, only the folders are merged
+			imp.summary.Skipped = append(imp.summary.Skipped, item.Title)
+			return nil, nil
+		case imp.strategy == entity.ImportStrategyOverwrite:
+			prev, err := uc.workspaceRepo.GetByID(exists.ID)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := uc.workspaceRepo.Update(&entity.Workspace{ID: exists.ID, Data: item.Data}); err != nil {
+				return nil, err
+			}
+			imp.overwritten = append(imp.overwritten, prev)
+			if item.Type == entity.WorkspaceTypeServer {
+				w, err := uc.workspaceRepo.GetByID(exists.ID)
+				if err != nil {
+					return nil, err
+				}
+				uc.Publish(entity.WorkspaceEventServerUpdated, w)
+			}
+			imp.summary.Overwritten = append(imp.summary.Overwritten, item.Title)
+			return &exists.ID, nil
+		case imp.strategy == entity.ImportStrategyRename:
+			title = imp.uniqueTitle(item.Type, item.Title, parentID)
+			imp.summary.Renamed = append(imp.summary.Renamed, title)
+		}
+	}
+
+	created, err := uc.workspaceRepo.Create(&entity.Workspace{
+		ParentID: parentID,
+		Type:     item.Type,
+		Title:    title,
+		Data:     item.Data,
+		Sort:     item.Sort,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imp.existing = append(imp.existing, created)
+	imp.created = append(imp.created, created.ID)
+	imp.summary.Add(item.Type)
+
+	return &created.ID, nil
+}
+
+func (imp *workspaceImport) findByTitle(t entity.WorkspaceType, title string, parentID *int64) *entity.Workspace {
+	for _, w := range imp.existing {
+		if w.Type != t || w.Title != title {
+			continue
+		}
+		if getParentID(w.ParentID) == getParentID(parentID) {
+			return w
+		}
+	}
+	return nil
+}
+
+func (imp *workspaceImport) uniqueTitle(t entity.WorkspaceType, title string, parentID *int64) string {
+	newTitle := fmt.Sprintf("%s %s", title, entity.WorkspaceDuplicatePostfix)
+	for i := 2; imp.findByTitle(t, newTitle, parentID) != nil; i++ {
+		newTitle = fmt.Sprintf("%s %s %d", title, entity.WorkspaceDuplicatePostfix, i)
+	}
+	return newTitle
+}
+
+func getParentID(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func getSort(w *entity.Workspace) int {
+	if w.Sort == nil {
+		return 0
 	}
+	return *w.Sort
 }
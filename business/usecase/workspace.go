@@ -16,16 +16,18 @@ type WorkspaceUseCase struct {
 	log                *logger.Zerolog
 	workspaceRepo      WorkspaceRepo
 	startupWorkspaceID *int64
+	appVersion         string
 	subscribers        []func(e entity.WorkspaceEvent, payload interface{})
 }
 
 // NewWorkspaceUseCase creates a new WorkspaceUseCase.
-func NewWorkspaceUseCase(ctx context.Context, log *logger.Zerolog, workspaceRepo WorkspaceRepo, startupWorkspaceID *int64) *WorkspaceUseCase {
+func NewWorkspaceUseCase(ctx context.Context, log *logger.Zerolog, workspaceRepo WorkspaceRepo, startupWorkspaceID *int64, appVersion string) *WorkspaceUseCase {
 	uc := &WorkspaceUseCase{
 		ctx:                ctx,
 		log:                log,
 		workspaceRepo:      workspaceRepo,
 		startupWorkspaceID: startupWorkspaceID,
+		appVersion:         appVersion,
 	}
 
 	return uc
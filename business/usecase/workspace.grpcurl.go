@@ -0,0 +1,50 @@
+// Package usecase provides business logic.
+package usecase
+
+import (
+	"os"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+// ImportGrpcurl creates the server and the query from the grpcurl command.
+func (uc *WorkspaceUseCase) ImportGrpcurl(payload map[string]interface{}) *entity.GUIResponse {
+	req := &entity.GrpcurlImportRequest{}
+	if err := req.Model(payload); err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+
+	cmd, warnings, err := entity.ParseGrpcurl(req.Command)
+	if err != nil {
+		return entity.ErrorGUIResponse(err)
+	}
+	for _, w := range warnings {
+		uc.log.Warn().Msgf("grpcurl import: %s", w)
+	}
+
+	files := make(map[string]string, 3)
+	for _, name := range []string{cmd.CACert, cmd.Cert, cmd.Key} {
+		if name == "" {
+			continue
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			uc.log.Error().Msgf("failed to read certificate: %v", err)
+			return entity.ErrorGUIResponse(err)
+		}
+		files[name] = string(data)
+	}
+
+	server, err := uc.workspaceRepo.Create(&entity.Workspace{
+		ParentID: req.FolderID,
+		Type:     entity.WorkspaceTypeServer,
+		Title:    cmd.Addr,
+		Data:     cmd.Server(files),
+	})
+	if err != nil {
+		uc.log.Error().Msgf("failed to create server: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return uc.CreateQuery(server.ID, cmd.Method, cmd.Query())
+}
@@ -2,6 +2,11 @@
 package usecase
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/forest33/warthog/business/entity"
 )
 
@@ -36,6 +41,26 @@ func (uc *WorkspaceUseCase) UpdateQuery(payload map[string]interface{}) *entity.
 		return entity.ErrorGUIResponse(err)
 	}
 
+	return uc.queryResponse(query)
+}
+
+// CreateQuery creates a saved query of the server.
+func (uc *WorkspaceUseCase) CreateQuery(serverID int64, title string, item *entity.WorkspaceItemQuery) *entity.GUIResponse {
+	query, err := uc.workspaceRepo.Create(&entity.Workspace{
+		ParentID: &serverID,
+		Type:     entity.WorkspaceTypeQuery,
+		Title:    title,
+		Data:     item,
+	})
+	if err != nil {
+		uc.log.Error().Msgf("failed to create query: %v", err)
+		return entity.ErrorGUIResponse(err)
+	}
+
+	return uc.queryResponse(query)
+}
+
+func (uc *WorkspaceUseCase) queryResponse(query *entity.Workspace) *entity.GUIResponse {
 	w, err := uc.workspaceRepo.Get()
 	if err != nil {
 		uc.log.Error().Msgf("failed to get workspace: %v", err)
@@ -45,7 +70,7 @@ func (uc *WorkspaceUseCase) UpdateQuery(payload map[string]interface{}) *entity.
 	server, err := uc.workspaceRepo.GetByID(*query.ParentID)
 	if err != nil {
 		uc.log.Error().
-			Int64("id", req.ID).
+			Int64("id", query.ID).
 			Msgf("failed to get server: %v", err)
 		return entity.ErrorGUIResponse(err)
 	}
@@ -65,3 +90,47 @@ func (uc *WorkspaceUseCase) UpdateQuery(payload map[string]interface{}) *entity.
 		},
 	}
 }
+
+// FindQuery returns a saved query by id or by breadcrumb path.
+func (uc *WorkspaceUseCase) FindQuery(ref string) (*entity.Workspace, error) {
+	return uc.Find(ref, entity.WorkspaceTypeQuery)
+}
+
+// Find returns a workspace item of one of the given types by id or by breadcrumb path.
+func (uc *WorkspaceUseCase) Find(ref string, types ...entity.WorkspaceType) (*entity.Workspace, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		item, err := uc.workspaceRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(types, item.Type) {
+			return nil, fmt.Errorf("wrong type of workspace item %d", id)
+		}
+		return item, nil
+	}
+
+	breadcrumb := strings.Split(strings.Trim(ref, entity.WorkspaceBreadcrumbSeparator), entity.WorkspaceBreadcrumbSeparator)
+	for i := range breadcrumb {
+		breadcrumb[i] = strings.TrimSpace(breadcrumb[i])
+	}
+
+	w, err := uc.workspaceRepo.Get()
+	if err != nil {
+		uc.log.Error().Msgf("failed to get workspace: %v", err)
+		return nil, err
+	}
+
+	found := make([]*entity.Workspace, 0, 1)
+	for _, t := range types {
+		found = append(found, entity.FindByBreadcrumb(w, t, breadcrumb)...)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("\"%s\" not found", ref)
+	case 1:
+		return found[0], nil
+	}
+
+	return nil, fmt.Errorf("\"%s\" is ambiguous, use the id", ref)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/forest33/warthog/business/entity"
+)
+
+const (
+	cliCommandRun = "run"
+
+	cliExitOK          = 0
+	cliExitQueryFailed = 1
+	cliExitError       = 2
+)
+
+func isCLI() bool {
+	return flag.Arg(0) == cliCommandRun
+}
+
+func runCLI(args []string) int {
+	fs := flag.NewFlagSet(cliCommandRun, flag.ContinueOnError)
+	queryRef := fs.String("query", "", "saved query id or breadcrumb path, e.g. \"Folder/Server/Query\"")
+	collectionRef := fs.String("collection", "", "folder or server id or breadcrumb path, runs all saved queries of the subtree")
+	reportFormat := fs.String("report", entity.RunReportFormatJSON, "collection report format: json or junit")
+	output := fs.String("output", "", "collection report file, stdout by default")
+	timeout := fs.Duration("timeout", 0, "maximum time to wait for the response of each query, 0 - no limit")
+	verbose := fs.Bool("verbose", false, "print progress messages to stderr")
+
+	if err := fs.Parse(args); err != nil {
+		return cliExitError
+	}
+
+	if (*queryRef == "") == (*collectionRef == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of the -query or -collection flags is required")
+		fs.Usage()
+		return cliExitError
+	}
+
+	if err := entity.CheckRunReportFormat(*reportFormat); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return cliExitError
+	}
+
+	go cliMessages(*verbose)
+
+	if *collectionRef != "" {
+		return runCLICollection(*collectionRef, *reportFormat, *output, *timeout)
+	}
+
+	return runCLIQuery(*queryRef, *timeout)
+}
+
+func runCLIQuery(ref string, timeout time.Duration) int {
+	query, err := workspaceUseCase.FindQuery(ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find query: %v\n", err)
+		return cliExitError
+	}
+
+	responses, runErr := grpcUseCase.RunQuery(query.ID, timeout)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	for _, r := range responses {
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+			return cliExitError
+		}
+	}
+
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to run query: %v\n", runErr)
+		return cliExitError
+	}
+
+	res := &entity.RunResult{}
+	res.SetResponses(responses, nil)
+	for _, f := range res.AssertionFailures {
+		fmt.Fprintf(os.Stderr, "assertion failed: %s\n", f)
+	}
+	if !res.Passed {
+		return cliExitQueryFailed
+	}
+
+	return cliExitOK
+}
+
+func runCLICollection(ref, format, output string, timeout time.Duration) int {
+	item, err := workspaceUseCase.Find(ref, entity.WorkspaceTypeFolder, entity.WorkspaceTypeServer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find folder or server: %v\n", err)
+		return cliExitError
+	}
+
+	report, err := grpcUseCase.RunCollection(item.ID, timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to run collection: %v\n", err)
+		return cliExitError
+	}
+
+	data, err := report.Marshal(format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to make report: %v\n", err)
+		return cliExitError
+	}
+
+	if output == "" {
+		fmt.Println(string(data))
+	} else if err := os.WriteFile(output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+		return cliExitError
+	}
+
+	fmt.Fprintf(os.Stderr, "total: %d passed: %d failed: %d errors: %d duration: %s\n",
+		report.Total, report.Passed, report.Failed, report.Errors, report.Duration)
+
+	if !report.IsPassed() {
+		return cliExitQueryFailed
+	}
+
+	return cliExitOK
+}
+
+func cliMessages(verbose bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-grpcUseCase.GetInfoChannel():
+			if verbose && m.State != nil {
+				fmt.Fprintf(os.Stderr, "%s connection: %s\n", m.State.Time.Format(time.TimeOnly), m.State)
+			} else if verbose && m.Message != "" {
+				fmt.Fprintf(os.Stderr, "%s %s\n", time.Now().Format(time.TimeOnly), m.Message)
+			}
+		case m := <-grpcUseCase.GetErrorChannel():
+			fmt.Fprintf(os.Stderr, "error: %s\n", m.Message)
+		}
+	}
+}
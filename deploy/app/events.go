@@ -34,6 +34,14 @@ func eventsHandler(r *entity.GUIRequest) *entity.GUIResponse {
 		resp = workspaceUseCase.Delete(payload)
 	case entity.CmdExpandWorkspace:
 		resp = workspaceUseCase.Expand(payload)
+	case entity.CmdExportWorkspace:
+		resp = workspaceUseCase.ExportFile(payload)
+	case entity.CmdImportWorkspace:
+		resp = workspaceUseCase.ImportFile(payload)
+	case entity.CmdImportGrpcurl:
+		resp = workspaceUseCase.ImportGrpcurl(payload)
+	case entity.CmdGenerateCerts:
+		resp = workspaceUseCase.GenerateCertificates(payload)
 	case entity.CmdCreateFolder:
 		resp = workspaceUseCase.CreateFolder(payload)
 	case entity.CmdUpdateFolder:
@@ -46,16 +54,42 @@ func eventsHandler(r *entity.GUIRequest) *entity.GUIResponse {
 		resp = workspaceUseCase.UpdateServer(payload)
 	case entity.CmdUpdateServerRequest:
 		resp = workspaceUseCase.UpdateServerRequest(payload)
+	case entity.CmdGetEnvironment:
+		resp = environmentUseCase.Get()
+	case entity.CmdCreateEnvironment:
+		resp = environmentUseCase.Create(payload)
+	case entity.CmdUpdateEnvironment:
+		resp = environmentUseCase.Update(payload)
+	case entity.CmdDeleteEnvironment:
+		resp = environmentUseCase.Delete(payload)
 	case entity.CmdUpdateQuery:
 		resp = workspaceUseCase.UpdateQuery(payload)
 	case entity.CmdLoadServer:
 		resp = grpcUseCase.LoadServer(payload)
 	case entity.CmdRunQuery:
 		resp = grpcUseCase.Query(payload)
+	case entity.CmdExportGrpcurl:
+		resp = grpcUseCase.ExportGrpcurl(payload)
+	case entity.CmdExportGoSnippet:
+		resp = grpcUseCase.ExportGoSnippet(payload)
 	case entity.CmdCancelQuery:
-		grpcUseCase.CancelQuery()
+		resp = grpcUseCase.CancelQuery(payload)
 	case entity.CmdCloseStream:
-		grpcUseCase.CloseStream()
+		resp = grpcUseCase.CloseStream(payload)
+	case entity.CmdGetVariables:
+		resp = grpcUseCase.GetVariables()
+	case entity.CmdClearVariables:
+		resp = grpcUseCase.ClearVariables()
+	case entity.CmdGetHistory:
+		resp = historyUseCase.Get(payload)
+	case entity.CmdRunHistory:
+		resp = grpcUseCase.RunHistory(payload)
+	case entity.CmdSaveHistory:
+		resp = historyUseCase.SaveAsQuery(payload)
+	case entity.CmdDeleteHistory:
+		resp = historyUseCase.Delete(payload)
+	case entity.CmdClearHistory:
+		resp = historyUseCase.Clear(payload)
 	case entity.CmdDevTools:
 		_ = window.OpenDevTools()
 	default:
@@ -88,6 +88,27 @@ func getMenuOptions() []*astilectron.MenuItemOptions {
 					Accelerator: astilectron.NewAccelerator("CommandOrControl+,"),
 					OnClick:     menuSettings,
 				},
+				{
+					Label:   astikit.StrPtr("Environments..."),
+					OnClick: menuEnvironments,
+				},
+				{
+					Label:       astikit.StrPtr("History..."),
+					Accelerator: astilectron.NewAccelerator("CommandOrControl+H"),
+					OnClick:     menuHistory,
+				},
+				{
+					Label:   astikit.StrPtr("Export workspace..."),
+					OnClick: menuExport,
+				},
+				{
+					Label:   astikit.StrPtr("Import workspace..."),
+					OnClick: menuImport,
+				},
+				{
+					Label:   astikit.StrPtr("Import grpcurl command..."),
+					OnClick: menuImportGrpcurl,
+				},
 				{
 					Label: astikit.StrPtr("Exit"),
 					Role:  astilectron.MenuItemRoleQuit,
@@ -239,7 +260,7 @@ func initGrpcResponse() {
 			select {
 			case <-ctx.Done():
 				return
-			case resp := <-grpcClient.GetResponseChannel():
+			case resp := <-grpcUseCase.GetResponseChannel():
 				req := &entity.GUIRequest{
 					Cmd:     entity.CmdQueryResponse,
 					Payload: resp,
@@ -264,6 +285,14 @@ func initAsyncMessages() {
 					Cmd:     entity.CmdMessageInfo,
 					Payload: resp,
 				}
+				if resp.State != nil {
+					req.Cmd = entity.CmdConnectionState
+					req.Payload = resp.State
+				}
+				if resp.Schema != nil {
+					req.Cmd = entity.CmdSchemaChanged
+					req.Payload = resp.Schema
+				}
 				err := window.SendMessage(req, func(_ *astilectron.EventMessage) {})
 				if err != nil {
 					zlog.Error().Msgf("failed to send info message: %v", err)
@@ -336,6 +365,46 @@ func menuSettings(e astilectron.Event) (deleteListener bool) {
 	return false
 }
 
+func menuEnvironments(e astilectron.Event) (deleteListener bool) {
+	err := window.SendMessage(&entity.GUIRequest{Cmd: entity.CmdMenuEnvironments}, func(_ *astilectron.EventMessage) {})
+	if err != nil {
+		zlog.Error().Msgf("failed to send message: %v", err)
+	}
+	return false
+}
+
+func menuHistory(e astilectron.Event) (deleteListener bool) {
+	err := window.SendMessage(&entity.GUIRequest{Cmd: entity.CmdMenuHistory}, func(_ *astilectron.EventMessage) {})
+	if err != nil {
+		zlog.Error().Msgf("failed to send message: %v", err)
+	}
+	return false
+}
+
+func menuImportGrpcurl(e astilectron.Event) (deleteListener bool) {
+	err := window.SendMessage(&entity.GUIRequest{Cmd: entity.CmdMenuImportGrpcurl}, func(_ *astilectron.EventMessage) {})
+	if err != nil {
+		zlog.Error().Msgf("failed to send message: %v", err)
+	}
+	return false
+}
+
+func menuExport(e astilectron.Event) (deleteListener bool) {
+	err := window.SendMessage(&entity.GUIRequest{Cmd: entity.CmdMenuExport}, func(_ *astilectron.EventMessage) {})
+	if err != nil {
+		zlog.Error().Msgf("failed to send message: %v", err)
+	}
+	return false
+}
+
+func menuImport(e astilectron.Event) (deleteListener bool) {
+	err := window.SendMessage(&entity.GUIRequest{Cmd: entity.CmdMenuImport}, func(_ *astilectron.EventMessage) {})
+	if err != nil {
+		zlog.Error().Msgf("failed to send message: %v", err)
+	}
+	return false
+}
+
 type applicationState struct {
 	State    *entity.WorkspaceState `json:"state"`
 	Settings *entity.Settings       `json:"settings"`
@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"flag"
+	"os"
 	"runtime"
 	"sync"
 
@@ -12,6 +13,7 @@ import (
 	db "github.com/forest33/warthog/adapter/database"
 	"github.com/forest33/warthog/adapter/grpc"
 	"github.com/forest33/warthog/adapter/k8s"
+	"github.com/forest33/warthog/adapter/ssh"
 	"github.com/forest33/warthog/business/entity"
 	"github.com/forest33/warthog/business/usecase"
 	"github.com/forest33/warthog/pkg/database"
@@ -47,14 +49,19 @@ var (
 	cfg = &entity.Config{}
 	dbi *database.Database
 
-	settingsRepo  *db.SettingsRepository
-	workspaceRepo *db.WorkspaceRepository
-	grpcClient    *grpc.Client
-	k8sClient     *k8s.Client
+	settingsRepo    *db.SettingsRepository
+	workspaceRepo   *db.WorkspaceRepository
+	environmentRepo *db.EnvironmentRepository
+	historyRepo     *db.HistoryRepository
+	grpcClient      *grpc.Client
+	k8sClient       *k8s.Client
+	sshClient       *ssh.Client
 
-	settingsUseCase  *usecase.SettingsUseCase
-	workspaceUseCase *usecase.WorkspaceUseCase
-	grpcUseCase      *usecase.GrpcUseCase
+	settingsUseCase    *usecase.SettingsUseCase
+	workspaceUseCase   *usecase.WorkspaceUseCase
+	environmentUseCase *usecase.EnvironmentUseCase
+	historyUseCase     *usecase.HistoryUseCase
+	grpcUseCase        *usecase.GrpcUseCase
 
 	settings *entity.Settings
 	ast      *astilectron.Astilectron
@@ -91,6 +98,9 @@ func main() {
 
 	zlog = logger.NewZerolog(logger.ZeroConfig{
 		Level: func() string {
+			if isCLI() {
+				return "error"
+			}
 			if entity.IsDebug() {
 				return cfg.Logger.Level
 			}
@@ -116,6 +126,12 @@ func main() {
 	initClients()
 	initUseCases()
 
+	if isCLI() {
+		code := runCLI(flag.Args()[1:])
+		shutdown()
+		os.Exit(code)
+	}
+
 	if UseBootstrap == "true" {
 		withBootstrap()
 	} else {
@@ -126,11 +142,14 @@ func main() {
 func initAdapters() {
 	settingsRepo = db.NewSettingsRepository(ctx, dbi)
 	workspaceRepo = db.NewWorkspaceRepository(ctx, dbi, zlog)
+	environmentRepo = db.NewEnvironmentRepository(ctx, dbi)
+	historyRepo = db.NewHistoryRepository(ctx, dbi)
 }
 
 func initClients() {
 	grpcClient = grpc.New(ctx, zlog)
 	k8sClient = k8s.New(ctx, zlog)
+	sshClient = ssh.New(ctx, zlog)
 }
 
 func initUseCases() {
@@ -139,11 +158,15 @@ func initUseCases() {
 	settings := initSettings()
 	grpcClient.SetSettings(settings)
 	k8sClient.SetSettings(settings)
+	sshClient.SetSettings(settings)
 
-	workspaceUseCase = usecase.NewWorkspaceUseCase(ctx, zlog, workspaceRepo, workspaceID)
+	workspaceUseCase = usecase.NewWorkspaceUseCase(ctx, zlog, workspaceRepo, workspaceID, AppVersion)
 	usecase.SetWorkspaceUseCase(workspaceUseCase)
 
-	grpcUseCase = usecase.NewGrpcUseCase(ctx, zlog, grpcClient, k8sClient, workspaceRepo)
+	environmentUseCase = usecase.NewEnvironmentUseCase(ctx, zlog, environmentRepo)
+	historyUseCase = usecase.NewHistoryUseCase(ctx, zlog, historyRepo)
+
+	grpcUseCase = usecase.NewGrpcUseCase(ctx, zlog, grpcClient, k8sClient, sshClient, workspaceRepo, environmentRepo, historyRepo)
 }
 
 func initSettings() *entity.Settings {
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS environment;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS environment
+(
+    id         INTEGER PRIMARY KEY,
+    name       TEXT UNIQUE NOT NULL,
+    variables  TEXT        NOT NULL DEFAULT '{}',
+    created_at DATETIME             DEFAULT (datetime('now', 'localtime')) NOT NULL,
+    updated_at DATETIME             DEFAULT (datetime('now', 'localtime')) NOT NULL
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS history
+(
+    id          INTEGER PRIMARY KEY,
+    server_id   INTEGER  NOT NULL,
+    service     TEXT     NOT NULL,
+    method      TEXT     NOT NULL,
+    data        TEXT     NOT NULL DEFAULT '{}',
+    input       TEXT     NULL,
+    metadata    TEXT     NOT NULL DEFAULT '[]',
+    response    TEXT     NOT NULL DEFAULT '',
+    header      TEXT     NOT NULL DEFAULT '{}',
+    trailer     TEXT     NOT NULL DEFAULT '{}',
+    status_code INTEGER  NOT NULL DEFAULT 0,
+    status      TEXT     NOT NULL DEFAULT '',
+    message     TEXT     NOT NULL DEFAULT '',
+    spent_time  TEXT     NOT NULL DEFAULT '',
+    created_at  DATETIME          DEFAULT (datetime('now', 'localtime')) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS history_server_id_idx ON history (server_id);
+CREATE INDEX IF NOT EXISTS history_created_at_idx ON history (created_at);
@@ -0,0 +1,8 @@
+ALTER TABLE settings
+    DROP COLUMN proxy_type;
+ALTER TABLE settings
+    DROP COLUMN proxy_addr;
+ALTER TABLE settings
+    DROP COLUMN proxy_login;
+ALTER TABLE settings
+    DROP COLUMN proxy_password;
@@ -0,0 +1,8 @@
+ALTER TABLE settings
+    ADD COLUMN proxy_type TEXT NOT NULL DEFAULT 'none';
+ALTER TABLE settings
+    ADD COLUMN proxy_addr TEXT NOT NULL DEFAULT '';
+ALTER TABLE settings
+    ADD COLUMN proxy_login TEXT NOT NULL DEFAULT '';
+ALTER TABLE settings
+    ADD COLUMN proxy_password TEXT NOT NULL DEFAULT '';
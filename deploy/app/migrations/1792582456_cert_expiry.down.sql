@@ -0,0 +1,2 @@
+ALTER TABLE settings
+    DROP COLUMN cert_expiry_warning_days;
@@ -0,0 +1,2 @@
+ALTER TABLE settings
+    ADD COLUMN cert_expiry_warning_days INTEGER NOT NULL DEFAULT 30;
@@ -0,0 +1,14 @@
+ALTER TABLE settings
+    DROP COLUMN call_compression;
+ALTER TABLE settings
+    DROP COLUMN call_max_recv_msg_size;
+ALTER TABLE settings
+    DROP COLUMN call_max_send_msg_size;
+ALTER TABLE settings
+    DROP COLUMN call_keepalive_time;
+ALTER TABLE settings
+    DROP COLUMN call_keepalive_timeout;
+ALTER TABLE settings
+    DROP COLUMN call_keepalive_permit_without_stream;
+ALTER TABLE settings
+    DROP COLUMN call_wait_for_ready;
@@ -0,0 +1,14 @@
+ALTER TABLE settings
+    ADD COLUMN call_compression TEXT NOT NULL DEFAULT 'identity';
+ALTER TABLE settings
+    ADD COLUMN call_max_recv_msg_size INTEGER NOT NULL DEFAULT 64;
+ALTER TABLE settings
+    ADD COLUMN call_max_send_msg_size INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE settings
+    ADD COLUMN call_keepalive_time INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE settings
+    ADD COLUMN call_keepalive_timeout INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE settings
+    ADD COLUMN call_keepalive_permit_without_stream BOOL NOT NULL DEFAULT FALSE;
+ALTER TABLE settings
+    ADD COLUMN call_wait_for_ready BOOL NOT NULL DEFAULT FALSE;
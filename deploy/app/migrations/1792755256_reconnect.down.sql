@@ -0,0 +1,6 @@
+ALTER TABLE settings
+    DROP COLUMN auto_reconnect;
+ALTER TABLE settings
+    DROP COLUMN reconnect_base_delay;
+ALTER TABLE settings
+    DROP COLUMN reconnect_max_delay;
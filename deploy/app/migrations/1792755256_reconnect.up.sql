@@ -0,0 +1,6 @@
+ALTER TABLE settings
+    ADD COLUMN auto_reconnect BOOL NOT NULL DEFAULT TRUE;
+ALTER TABLE settings
+    ADD COLUMN reconnect_base_delay INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE settings
+    ADD COLUMN reconnect_max_delay INTEGER NOT NULL DEFAULT 120;
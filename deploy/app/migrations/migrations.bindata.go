@@ -8,6 +8,18 @@
 // migrations/1677165148_k8s.up.sql
 // migrations/1714890607_settings.down.sql
 // migrations/1714890607_settings.up.sql
+// migrations/1792323256_environments.down.sql
+// migrations/1792323256_environments.up.sql
+// migrations/1792409656_history.down.sql
+// migrations/1792409656_history.up.sql
+// migrations/1792496056_proxy.down.sql
+// migrations/1792496056_proxy.up.sql
+// migrations/1792582456_cert_expiry.down.sql
+// migrations/1792582456_cert_expiry.up.sql
+// migrations/1792668856_call_options.down.sql
+// migrations/1792668856_call_options.up.sql
+// migrations/1792755256_reconnect.down.sql
+// migrations/1792755256_reconnect.up.sql
 package migrations
 
 import (
@@ -84,7 +96,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _migrations1633685677_initDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2f\xcd\x8c\x4f\xce\xcf\x4b\xcb\x4c\xb7\x06\x04\x00\x00\xff\xff\x49\xa7\x32\xcb\x20\x00\x00\x00")
+var _migrations1633685677_initDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x20\x00\xdf\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x67\x75\x69\x5f\x63\x6f\x6e\x66\x69\x67\x3b\x03\x00\x49\xa7\x32\xcb\x20\x00\x00\x00")
 
 func migrations1633685677_initDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -99,12 +111,12 @@ func migrations1633685677_initDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1633685677_init.down.sql", size: 32, mode: os.FileMode(436), modTime: time.Unix(1665214714, 0)}
+	info := bindataFileInfo{name: "migrations/1633685677_init.down.sql", size: 32, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1633685677_initUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x93\x51\x6f\xb2\x30\x14\x86\xef\xf9\x15\xe7\x0e\x48\xbc\xf0\xfb\x32\xb7\x25\xbb\x42\xad\x5b\x23\xc2\x02\x75\xd1\x2b\xd2\xd0\x2a\xcd\x18\x10\xa8\x41\xff\xfd\x52\x50\x04\x67\x74\x8b\xdb\xb9\x20\xf4\xe4\x3d\x6f\xdb\xe7\x9c\x8e\x3c\x64\x11\x04\xc4\x1a\xda\x08\xf0\x04\x1c\x97\x00\x5a\x60\x9f\xf8\xb0\xde\x88\x20\x4c\x93\x95\x58\x6b\x86\x06\x00\x50\x8a\x84\xa5\x65\x50\x0a\x26\x23\x00\xec\x90\x4a\xed\xcc\x6d\x1b\xc6\x68\x62\xcd\x6d\x02\xff\xfa\xff\xef\x7a\x6d\x71\xc4\xc5\x3a\x92\xe7\xc5\x0f\xf7\x8f\x1d\xed\x16\xaa\x38\xab\x1d\xf4\x3b\xd2\xdd\x37\xa4\x61\xce\xa9\xe4\x2c\xa0\x52\xad\xc6\x16\x41\x04\xcf\x50\x55\x77\x90\x1a\x8c\x4a\x2e\xc5\x07\x37\xf4\x24\x2d\xf5\x1e\xe8\x71\x1a\xd2\x58\x65\x74\xd3\x6c\xac\x6b\xbf\x4d\xc6\x7e\xc9\x4f\x33\x9f\x34\x0d\x3b\x3e\xf2\x88\xba\x82\xdb\x42\x0d\x46\x9b\x72\xaf\x8b\xb1\x59\x6e\x9b\xbf\x9d\xa9\xbd\x59\xf6\x1c\xf9\x60\x54\xec\x2b\xa8\x0a\x01\x0c\xfa\x6a\x97\x0b\xfd\x2d\xd3\xfc\xbd\xc8\x68\xc8\xf7\xed\x15\x0c\x0e\x81\x1d\x82\x9e\x91\x07\xaf\x1e\x9e\x59\xde\x12\xa6\x68\x59\x33\xc8\x68\xce\x13\x19\x28\xe9\x41\x73\x31\x8e\xf4\x22\x5a\x04\x61\x24\x62\x06\x30\x74\x5d\xfb\x72\x99\xaa\x3c\x6d\xeb\xc4\xb2\x7d\x54\x7b\xc9\x5d\xc6\xf7\x32\x82\x16\x04\x46\x2f\x68\x34\x05\xa3\x4a\x63\x07\x0c\x7d\xa5\xd0\x17\xea\x93\x7f\xed\xa3\x14\x32\xe6\xc7\xea\x3a\xc9\xa8\xa4\x6d\xcb\xab\xc7\x6b\xec\x8a\x34\x97\x27\xd8\x7e\x76\xb1\xfd\xb8\xf2\x6d\x46\x13\xc6\x55\x13\x6e\x06\xd4\x1a\xfd\xce\x9c\x5e\x8d\x5b\x1f\xc6\x9f\xee\xa6\x9e\xcd\x67\x00\x00\x00\xff\xff\x4d\x4a\x45\x79\xb0\x04\x00\x00")
+var _migrations1633685677_initUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x93\x5d\xcf\x9a\x30\x14\x80\xef\xfb\x2b\xce\x1d\x90\x78\xe1\x96\xbd\xdb\x12\xaf\x50\xeb\xd6\x88\xb0\x40\x5d\xf4\x8a\x34\xb4\x4a\x33\x06\x04\x6a\xd0\x7f\xbf\x94\x2f\x71\x4e\xa7\x89\x6f\x49\x48\x69\x9e\x9e\x93\xf3\x9c\xc3\xcc\xc7\x36\xc5\x40\xed\xa9\x83\x81\x2c\xc0\xf5\x28\xe0\x0d\x09\x68\x00\xfb\x83\x0c\xa3\x2c\xdd\xc9\x3d\x32\x11\x00\x40\x25\x53\x9e\x55\x61\x25\xb9\x8a\x01\x88\x4b\x6b\xda\x5d\x3b\x0e\xcc\xf1\xc2\x5e\x3b\x14\x3e\x8c\x3f\x7e\x1a\x0d\xe1\x58\xc8\x7d\xac\xfe\x0d\x7f\xf9\xfc\xf5\x82\x3d\xea\xfd\x8d\xc0\x6f\xe3\x0b\xf4\xf4\x00\x1a\x15\x82\x29\xc1\x43\xa6\x34\x3a\xb7\x29\xa6\x64\x85\xf5\xbe\x47\x4d\xce\x94\x50\xf2\xb7\x30\x8d\x34\xab\x8c\x11\x18\x49\x16\xb1\x44\x9f\x18\x96\xd5\x87\x6e\x52\x1f\x72\xfe\xa2\x78\xc8\x9a\x20\x44\xdc\x00\xfb\x54\x57\xeb\x0d\x54\x83\xd9\x56\x58\x5b\x1e\x75\xf5\x36\x1a\xfb\xcf\x63\xbf\x3b\x59\xe8\xa7\xed\xac\x71\x00\x66\xed\xbe\x96\xaa\x15\xc0\xdb\x58\x67\xb9\xd3\xdf\x2a\x2b\x7e\x95\x39\x8b\x44\xdb\x5e\xc9\xa1\x5b\xc4\xa5\xf8\x1b\xf6\xe1\x87\x4f\x56\xb6\xbf\x85\x25\xde\x36\x0e\x72\x56\x88\x54\x85\x92\x9f\x99\xbb\xeb\x6c\x2f\x66\x65\x18\xc5\x32\xe1\x00\x53\xcf\x73\x3a\xe0\xe6\xea\x5c\xf5\x6e\x17\xb6\x13\xe0\x26\x96\x3a\xe5\xa2\xc5\x28\xde\x50\x98\x7d\xc7\xb3\x25\x98\xf5\x31\x71\xc1\x34\x76\xba\x95\xa5\x7e\x15\xd7\x7d\x54\x52\x25\xed\x75\x7d\xbb\x39\xe4\x4c\xb1\x61\x48\x80\x47\x0b\x2b\xb3\x42\xfd\xa5\xed\xb9\xc2\xda\x71\x15\xc7\x9c\xa5\x5c\x70\x78\x81\xa0\xc1\xe8\x5f\xcc\xe9\x7f\x9f\x27\x07\xf9\xea\xc7\x78\xd7\x6c\xc8\x9a\xa0\x3f\x03\x00\x4d\x4a\x45\x79\xb0\x04\x00\x00")
 
 func migrations1633685677_initUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -119,12 +131,12 @@ func migrations1633685677_initUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1633685677_init.up.sql", size: 1200, mode: os.FileMode(436), modTime: time.Unix(1669023217, 0)}
+	info := bindataFileInfo{name: "migrations/1633685677_init.up.sql", size: 1200, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1668845636_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcf\x4d\xce\x82\x40\x0c\xc6\xf1\x3d\xa7\xe8\x3d\x58\xf1\xbe\xce\x8e\x0f\x43\x70\xdd\xc0\x50\x87\x46\xa6\x45\xa6\x24\x7a\x7b\x63\xe2\xd2\xc5\x78\x80\x5f\x9e\xe7\x5f\xd5\x83\xeb\x61\xa8\xfe\x6a\x07\x89\xcc\x58\x42\x2a\x00\x00\x4e\x7d\x77\x86\xff\xae\xbe\x34\x2d\x24\x96\xb0\x12\xb2\x24\x1b\xc5\x53\x59\x64\x29\xaf\x22\xe4\x0d\x8d\x23\xe9\x61\x99\x6a\xa7\xfb\x41\xe9\x57\x25\x2a\x38\xad\xea\x6f\x2c\x01\x3f\xc3\xac\x92\xa9\x93\xee\x86\x91\x6c\xd1\x39\xe1\xf4\x44\x19\x63\x6e\x64\x1c\x1f\xb8\xaa\x6e\x38\xd3\x66\x4b\x59\x7c\x55\xd0\xbb\xb6\x6a\x1c\x0c\x1d\x84\x83\xdf\xff\xae\x1c\xca\x57\x00\x00\x00\xff\xff\x8c\x3b\x19\xa0\x7b\x01\x00\x00")
+var _migrations1668845636_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcf\x3d\x0e\x82\x40\x10\x40\xe1\x9e\x53\xcc\x3d\xa8\x50\xb7\xe3\xc7\x10\xac\x27\xb0\x8c\xcb\x44\x76\x06\xd9\x21\xd1\xdb\xdb\x58\x5a\xac\x07\xf8\xf2\xf2\xaa\x7a\x70\x3d\x0c\xd5\xa9\x76\x90\xc8\x8c\x25\xa4\x02\x00\xe0\xd2\x77\x57\x38\x77\xf5\xad\x69\x21\xb1\x84\x95\x90\x25\xd9\x28\x9e\xca\x22\x4b\x79\x15\x21\x6f\x68\x1c\x49\x0f\xcb\x54\x3b\x3d\x0f\x4a\xff\x2a\x51\xc1\x69\x55\xff\x60\x09\xf8\x0d\xb3\x4a\xa6\x4e\xba\x1b\x46\xb2\x45\xe7\x84\xd3\x1b\x65\x8c\xb9\x93\x71\x7c\xe1\xaa\xba\xe1\x4c\x9b\x2d\x65\xf1\x53\x41\xef\xda\xaa\x71\x30\x74\x10\x0e\x46\xaf\x72\xe7\x50\x7e\x06\x00\x8c\x3b\x19\xa0\x7b\x01\x00\x00")
 
 func migrations1668845636_settingsDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -139,12 +151,12 @@ func migrations1668845636_settingsDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1668845636_settings.down.sql", size: 379, mode: os.FileMode(436), modTime: time.Unix(1669023217, 0)}
+	info := bindataFileInfo{name: "migrations/1668845636_settings.down.sql", size: 379, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1668845636_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xd0\xc1\x4a\x03\x31\x10\x06\xe0\xfb\x3e\xc5\x3c\x82\xe2\xb1\xa7\xd4\x8d\x20\xa4\x09\x2c\xd9\xf3\xb0\x4d\xc7\x34\xb8\x99\xa9\xcd\x2c\xe8\xdb\x0b\xa2\x20\x88\x74\x61\xaf\xc3\xcf\xc7\xfc\xbf\x71\xd1\x0e\x10\xcd\xde\x59\xc8\x4b\xc1\x24\xfc\x52\x72\x07\x00\x30\x58\x6f\x0e\x16\x62\x80\x46\xaa\x85\x73\xdb\x75\xdd\xef\xfc\xcf\xf9\x2b\x6d\xfa\x1e\x1e\x83\x1b\x0f\x1e\x5a\xe1\x3c\x13\x16\x6e\x3a\x71\x22\xd8\x87\xe0\xc0\x87\x08\x7e\x74\x0e\x7a\xfb\x64\x46\x17\x21\x0e\xa3\xdd\xad\xf2\x92\x30\x53\x52\xd4\x52\x49\x16\x85\x67\x1f\xff\x72\xf7\x77\xeb\xb0\x2b\xbd\x2d\xd4\x6e\x60\x0f\x2b\x31\x16\xc6\xe3\x2c\xe9\xb5\x70\xc6\xef\x37\x8b\xf0\xe6\xc6\x4d\xae\x8a\x95\xf4\x2c\xa7\x86\xc7\x0f\xe4\xa9\x6e\x9f\xb1\x4e\xef\x38\x8b\x5c\xf0\x44\x17\x3d\xff\xbb\xe2\x67\x00\x00\x00\xff\xff\x8b\x20\x22\x7f\x12\x02\x00\x00")
+var _migrations1668845636_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xd0\xc1\x4a\x03\x31\x10\x06\xe0\xfb\x3e\xc5\x3c\x82\xe2\xb1\xa7\xd4\x8d\x20\xa4\x09\x2c\xd9\xf3\xb0\x4d\xc7\x34\xb8\x99\xa9\xcd\x2c\xe8\xdb\x0b\xa2\x20\x88\x74\x61\xaf\xc3\xcf\xc7\xfc\xbf\x71\xd1\x0e\x10\xcd\xde\x59\xc8\x4b\xc1\x24\xfc\x52\x72\x07\x00\x30\x58\x6f\x0e\x16\x62\x80\x46\xaa\x85\x73\xdb\x75\xdd\xef\xfc\xcf\xf9\x2b\x6d\xfa\x1e\x1e\x83\x1b\x0f\x1e\x5a\xe1\x3c\x13\x16\x6e\x3a\x71\x22\xd8\x87\xe0\xc0\x87\x08\x7e\x74\x0e\x7a\xfb\x64\x46\x17\x21\x0e\xa3\xdd\xad\xf2\x92\x30\x53\x52\xd4\x52\x49\x16\x85\x67\x1f\xff\x72\xf7\x77\xeb\xb0\x2b\xbd\x2d\xd4\x6e\x60\x0f\x2b\x31\x16\xc6\xe3\x2c\xe9\xb5\x70\xc6\xef\x37\x8b\xf0\xe6\xc6\x4d\xae\x8a\x95\xf4\x2c\xa7\x86\xc7\x0f\xe4\xa9\x6e\x9f\xb1\x4e\xef\x38\x8b\x5c\xf0\x44\x17\x3d\xff\xbb\xe2\xe7\x00\x8b\x20\x22\x7f\x12\x02\x00\x00")
 
 func migrations1668845636_settingsUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -159,12 +171,12 @@ func migrations1668845636_settingsUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1668845636_settings.up.sql", size: 530, mode: os.FileMode(436), modTime: time.Unix(1669023217, 0)}
+	info := bindataFileInfo{name: "migrations/1668845636_settings.up.sql", size: 530, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1677165148_k8sDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x50\x50\x50\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xc8\xb6\x28\x8e\x2f\x4a\x2d\x2c\x4d\x2d\x2e\x89\x2f\xc9\xcc\x4d\xcd\x2f\x2d\xb1\xe6\x02\x04\x00\x00\xff\xff\x60\x26\x95\x49\x3a\x00\x00\x00")
+var _migrations1677165148_k8sDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3a\x00\xc5\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x0a\x20\x20\x20\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x6b\x38\x73\x5f\x72\x65\x71\x75\x65\x73\x74\x5f\x74\x69\x6d\x65\x6f\x75\x74\x3b\x0a\x03\x00\x60\x26\x95\x49\x3a\x00\x00\x00")
 
 func migrations1677165148_k8sDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -179,12 +191,12 @@ func migrations1677165148_k8sDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1677165148_k8s.down.sql", size: 58, mode: os.FileMode(436), modTime: time.Unix(1677388568, 0)}
+	info := bindataFileInfo{name: "migrations/1677165148_k8s.down.sql", size: 58, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1677165148_k8sUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x04\xc0\xc1\x0a\xc2\x30\x0c\x06\xe0\x7b\x9f\xe2\x7f\x04\xc1\x8b\xe0\x29\xda\x0a\x42\x4c\x41\xd2\x73\x4f\x41\x8a\x6c\x63\x4b\xfa\xfe\xfb\x88\xb5\x7c\xa1\xf4\xe0\x02\xb7\x88\xb1\xfe\x3c\x01\x00\xe5\x8c\x67\xe5\xf6\x11\xfc\x6f\xde\x0f\xdb\xa7\x79\xf4\x18\x8b\x6d\x33\xf0\x16\x85\x54\x85\x34\x66\xe4\xf2\xa2\xc6\x8a\xeb\xe5\x9e\xce\x00\x00\x00\xff\xff\x70\xdf\x52\x1c\x51\x00\x00\x00")
+var _migrations1677165148_k8sUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x51\x00\xae\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x0a\x20\x20\x20\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x6b\x38\x73\x5f\x72\x65\x71\x75\x65\x73\x74\x5f\x74\x69\x6d\x65\x6f\x75\x74\x20\x49\x4e\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x33\x30\x3b\x0a\x03\x00\x70\xdf\x52\x1c\x51\x00\x00\x00")
 
 func migrations1677165148_k8sUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -199,12 +211,12 @@ func migrations1677165148_k8sUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1677165148_k8s.up.sql", size: 81, mode: os.FileMode(436), modTime: time.Unix(1677388568, 0)}
+	info := bindataFileInfo{name: "migrations/1677165148_k8s.up.sql", size: 81, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1714890607_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x50\x50\x50\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\xcd\xcd\x2c\x89\x4f\x49\x4d\x4b\x2c\xcd\x29\x29\xb6\xe6\x22\x4a\x4f\x72\x46\x6a\x72\x76\x7c\x69\x41\x4a\x62\x49\x6a\xb1\x35\x17\x20\x00\x00\xff\xff\x53\x48\x0b\x27\x68\x00\x00\x00")
+var _migrations1714890607_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x68\x00\x97\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x0a\x20\x20\x20\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x65\x6d\x69\x74\x5f\x64\x65\x66\x61\x75\x6c\x74\x73\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x0a\x20\x20\x20\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x63\x68\x65\x63\x6b\x5f\x75\x70\x64\x61\x74\x65\x73\x3b\x0a\x03\x00\x53\x48\x0b\x27\x68\x00\x00\x00")
 
 func migrations1714890607_settingsDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -219,12 +231,12 @@ func migrations1714890607_settingsDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1714890607_settings.down.sql", size: 104, mode: os.FileMode(436), modTime: time.Unix(1714900169, 0)}
+	info := bindataFileInfo{name: "migrations/1714890607_settings.down.sql", size: 104, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations1714890607_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xcc\xcb\x0d\xc2\x30\x0c\x06\xe0\x7b\xa6\xf8\xf7\xe8\xc9\x25\xee\xc9\x24\x52\xb1\xcf\x55\xd5\x1a\xa8\x78\x08\x29\xce\xfe\x4c\x80\xc4\x02\x1f\x89\xf2\x0c\xa5\x51\x18\xcd\x23\x8e\xf7\xad\x25\x00\xa0\x9c\x71\xaa\x62\xe7\x02\x7f\x1d\xb1\xec\x7e\x5d\xfb\x33\x1a\xc6\x5a\x05\xa5\x2a\x8a\x89\x20\xf3\x44\x26\x8a\x89\xe4\xc2\x43\xfa\x87\xdb\xee\xbe\x3d\x96\xfe\xd9\xd7\xf0\x5f\x9c\xce\xc6\x43\xfa\x06\x00\x00\xff\xff\x38\xa7\xf2\x02\x9d\x00\x00\x00")
+var _migrations1714890607_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xcc\xcb\x0d\xc2\x30\x0c\x06\xe0\x7b\xa6\xf8\xf7\xe8\xc9\x25\xee\xc9\x24\x52\xb1\xcf\x55\xd5\x1a\xa8\x78\x08\x29\xce\xfe\x4c\x80\xc4\x02\x1f\x89\xf2\x0c\xa5\x51\x18\xcd\x23\x8e\xf7\xad\x25\x00\xa0\x9c\x71\xaa\x62\xe7\x02\x7f\x1d\xb1\xec\x7e\x5d\xfb\x33\x1a\xc6\x5a\x05\xa5\x2a\x8a\x89\x20\xf3\x44\x26\x8a\x89\xe4\xc2\x43\xfa\x87\xdb\xee\xbe\x3d\x96\xfe\xd9\xd7\xf0\x5f\x9c\xce\xc6\x43\xfa\x0e\x00\x38\xa7\xf2\x02\x9d\x00\x00\x00")
 
 func migrations1714890607_settingsUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -239,7 +251,247 @@ func migrations1714890607_settingsUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations/1714890607_settings.up.sql", size: 157, mode: os.FileMode(436), modTime: time.Unix(1714900153, 0)}
+	info := bindataFileInfo{name: "migrations/1714890607_settings.up.sql", size: 157, mode: os.FileMode(436), modTime: time.Unix(1741263494, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792323256_environmentsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x21\x00\xde\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x6e\x76\x69\x72\x6f\x6e\x6d\x65\x6e\x74\x3b\x03\x00\x4e\xa4\x87\x73\x21\x00\x00\x00")
+
+func migrations1792323256_environmentsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792323256_environmentsDownSql,
+		"migrations/1792323256_environments.down.sql",
+	)
+}
+
+func migrations1792323256_environmentsDownSql() (*asset, error) {
+	bytes, err := migrations1792323256_environmentsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792323256_environments.down.sql", size: 33, mode: os.FileMode(420), modTime: time.Unix(1792323259, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792323256_environmentsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x8e\xb1\x4e\xc3\x30\x10\x86\xf7\x3c\xc5\xbf\x39\x91\xfa\x06\x4c\x86\x5e\x91\x45\x6a\xc0\x3d\x4b\xed\x84\x4c\x7d\x83\xa5\xc4\x41\xc1\x94\x01\xf1\xee\x88\xc6\x59\x98\x39\xdd\x74\xfa\xbe\x4f\x77\xe7\x48\x33\x81\xf5\x6d\x4f\x30\x3b\xd8\x47\x06\x1d\xcd\x81\x0f\x90\x7c\x49\xf3\x94\x47\xc9\xa5\x69\x1b\x00\x48\x11\xeb\x18\xcb\x74\x4f\x0e\x4f\xce\xec\xb5\x3b\xe1\x81\x4e\x9b\x2b\x93\xc3\x28\x95\x61\x3a\x32\xbc\x35\xcf\x9e\xae\x5d\xeb\xfb\x7e\x81\x2e\x61\x4e\xe1\x75\x90\xf7\x0a\x55\x61\x85\xb0\xa5\x9d\xf6\x3d\x43\x7d\x7d\xab\xc5\x38\xcf\x12\x8a\xc4\x97\x50\xb0\xd5\x4c\x6c\xf6\x54\xa5\x65\x57\xa3\x8d\xa1\x48\x49\xa3\xb4\x2a\x4f\x9f\x6a\x03\x35\x4c\xe7\x30\xfc\x5e\x54\xd7\xfd\x79\xe3\xe3\x2d\xfe\x67\xb4\xe9\x6e\x9a\x9f\x01\x00\xa1\xde\xf1\xd4\x50\x01\x00\x00")
+
+func migrations1792323256_environmentsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792323256_environmentsUpSql,
+		"migrations/1792323256_environments.up.sql",
+	)
+}
+
+func migrations1792323256_environmentsUpSql() (*asset, error) {
+	bytes, err := migrations1792323256_environmentsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792323256_environments.up.sql", size: 336, mode: os.FileMode(420), modTime: time.Unix(1792323273, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792409656_historyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1d\x00\xe2\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x68\x69\x73\x74\x6f\x72\x79\x3b\x03\x00\x49\xd0\x3f\x4e\x1d\x00\x00\x00")
+
+func migrations1792409656_historyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792409656_historyDownSql,
+		"migrations/1792409656_history.down.sql",
+	)
+}
+
+func migrations1792409656_historyDownSql() (*asset, error) {
+	bytes, err := migrations1792409656_historyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792409656_history.down.sql", size: 29, mode: os.FileMode(420), modTime: time.Unix(1792323950, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792409656_historyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xd2\xcf\x6b\xc2\x30\x14\x07\xf0\x7b\xff\x8a\xef\xad\x2d\x78\xd8\xdd\x53\x37\xe3\x28\xab\x75\xd4\x08\xca\x18\x25\x34\x8f\x19\xd0\x56\x92\xe7\x7e\x30\xf6\xbf\x8f\xba\x1a\x65\x6e\xda\x5e\x1a\xf2\x3e\x7c\xf3\x48\xde\x5d\x21\x12\x29\x20\x93\xdb\x4c\x20\x1d\x23\x9f\x4a\x88\x45\x3a\x93\x33\xac\x8c\xe3\xc6\x7e\x04\x51\x00\x00\x46\xc3\x7f\x69\x2e\xc5\xbd\x28\xf0\x58\xa4\x93\xa4\x58\xe2\x41\x2c\x07\x7b\xe4\xc8\xbe\x92\x2d\x8d\x3e\x41\xfb\xc8\x7c\x9e\x65\x47\x62\x2a\x6a\x97\x90\x62\x21\xdb\xff\x2f\xb2\x21\x5e\x35\x1a\x97\x88\x56\xac\x80\x7f\x08\x46\x62\x9c\xcc\x33\x89\xf0\xf3\x2b\xfc\xf1\xa6\xde\xee\xf8\xcc\x9f\x9e\xa8\x0e\x91\x17\xe2\x9e\x9e\xbb\x38\x4b\x6e\xdb\xd4\x8e\xae\xf8\x4e\xaf\x48\x69\xb2\xfd\x9b\x65\xab\xcc\x9a\x6c\x6f\xef\x58\xf1\xce\x95\x55\xa3\xe9\xfc\xd6\xbd\xbf\x39\xc5\x57\x9b\xe9\xa2\x37\xe4\x9c\x7a\xa1\x9e\xda\x6d\xa9\xe6\x92\xcd\x86\xfa\xe8\xca\x92\x62\xd2\xa5\x62\x60\x94\x48\x21\xd3\x89\xe8\x9e\x08\xf0\x3a\xd2\x8a\xa9\x8d\x8c\xc2\xba\x79\x0b\x07\x08\xd7\x4d\xa5\xd6\xed\x4e\x18\xc7\x3e\x3d\x88\x87\x41\xd0\x0d\x73\x9a\x8f\xc4\xe2\xef\x61\x2e\xfd\x84\x96\x46\xbf\x63\x9a\x1f\x0a\x88\x7c\x25\x1e\xf6\x09\x3a\x76\x7f\x96\x74\x2c\xc5\xc3\xe0\x7b\x00\x7d\x2d\x21\x99\x61\x03\x00\x00")
+
+func migrations1792409656_historyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792409656_historyUpSql,
+		"migrations/1792409656_history.up.sql",
+	)
+}
+
+func migrations1792409656_historyUpSql() (*asset, error) {
+	bytes, err := migrations1792409656_historyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792409656_history.up.sql", size: 865, mode: os.FileMode(420), modTime: time.Unix(1792323950, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792496056_proxyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x00\x02\x97\x20\xff\x00\x05\x67\x7f\x9f\x50\x5f\x3f\x85\x82\xa2\xfc\x8a\xca\xf8\x92\xca\x82\x54\x6b\x2e\x47\xe2\x35\x24\xa6\xa4\x14\x91\xa4\x21\x27\x3f\x3d\x33\x8f\x24\x1d\x05\x89\xc5\xc5\xe5\xf9\x45\x29\xd6\x5c\x00\x8d\x24\xae\xc2\xc9\x00\x00\x00")
+
+func migrations1792496056_proxyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792496056_proxyDownSql,
+		"migrations/1792496056_proxy.down.sql",
+	)
+}
+
+func migrations1792496056_proxyDownSql() (*asset, error) {
+	bytes, err := migrations1792496056_proxyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792496056_proxy.down.sql", size: 201, mode: os.FileMode(420), modTime: time.Unix(1792496056, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792496056_proxyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x00\x02\x47\x17\x17\x05\x67\x7f\x9f\x50\x5f\x3f\x85\x82\xa2\xfc\x8a\xca\xf8\x92\xca\x82\x54\x85\x10\xd7\x88\x10\x05\x3f\x7f\x20\x0e\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\xcf\xcb\xcf\x4b\x55\xb7\xe6\x72\x24\xda\xb0\xc4\x94\x94\x22\x5c\x86\x91\x64\x50\x4e\x7e\x7a\x66\x1e\x55\x4c\x2a\x48\x2c\x2e\x2e\xcf\x2f\x4a\xc1\x63\x18\x00\x2d\xaa\xa2\x48\x2d\x01\x00\x00")
+
+func migrations1792496056_proxyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792496056_proxyUpSql,
+		"migrations/1792496056_proxy.up.sql",
+	)
+}
+
+func migrations1792496056_proxyUpSql() (*asset, error) {
+	bytes, err := migrations1792496056_proxyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792496056_proxy.up.sql", size: 301, mode: os.FileMode(420), modTime: time.Unix(1792496056, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792582456_cert_expiryDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x00\x02\x97\x20\xff\x00\x05\x67\x7f\x9f\x50\x5f\x3f\x85\xe4\xd4\xa2\x92\xf8\xd4\x8a\x82\xcc\xa2\xca\xf8\xf2\xc4\xa2\x3c\xa0\xa2\xf8\x94\xc4\xca\x62\x6b\x2e\x00\x31\x18\x2c\xc0\x3f\x00\x00\x00")
+
+func migrations1792582456_cert_expiryDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792582456_cert_expiryDownSql,
+		"migrations/1792582456_cert_expiry.down.sql",
+	)
+}
+
+func migrations1792582456_cert_expiryDownSql() (*asset, error) {
+	bytes, err := migrations1792582456_cert_expiryDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792582456_cert_expiry.down.sql", size: 63, mode: os.FileMode(420), modTime: time.Unix(1792582456, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792582456_cert_expiryUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x00\x02\x47\x17\x17\x05\x67\x7f\x9f\x50\x5f\x3f\x85\xe4\xd4\xa2\x92\xf8\xd4\x8a\x82\xcc\xa2\xca\xf8\xf2\xc4\xa2\x3c\xa0\x9a\xf8\x94\xc4\xca\x62\x05\x4f\xbf\x10\x57\x77\xa0\x76\x3f\xff\x10\x05\xbf\x50\x1f\x1f\x05\x17\x57\x37\xc7\x50\x9f\x10\x05\x63\x03\x6b\x2e\x00\x63\x9c\x1d\xbe\x5a\x00\x00\x00")
+
+func migrations1792582456_cert_expiryUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792582456_cert_expiryUpSql,
+		"migrations/1792582456_cert_expiry.up.sql",
+	)
+}
+
+func migrations1792582456_cert_expiryUpSql() (*asset, error) {
+	bytes, err := migrations1792582456_cert_expiryUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792582456_cert_expiry.up.sql", size: 90, mode: os.FileMode(420), modTime: time.Unix(1792582456, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792668856_call_optionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\xd0\x41\x12\x82\x30\x0c\x85\xe1\x3d\xa7\xe8\x3d\x58\x55\x65\x57\xc5\x61\x60\x9d\xc9\x40\xc4\x8c\x2d\x65\x9a\x08\xea\xe9\xed\x11\x04\xdf\xfe\xfb\x17\xcf\xba\xb6\x6a\x4c\x6b\x0f\xae\x32\x42\xaa\x3c\x8d\x52\x98\xbc\x53\x53\x5f\xcd\xb1\x76\xdd\xf9\x62\x7a\xf4\x1e\xfa\x18\xe6\x44\x22\x1c\xa7\xb2\xb0\x3f\xb3\x80\x2f\x48\xd4\x2f\x10\x64\x04\xe1\x0f\x6d\xc5\x42\xd3\xb0\x0b\x3f\x88\x66\xf4\xbc\x10\x28\x87\x3f\x64\x7c\xea\x3e\x3c\x53\x0a\xac\xb0\xb2\xde\x73\x03\x44\x13\x61\xd8\x92\x5a\x31\xf3\x5b\x4c\xf9\x40\x1c\xde\x65\xf1\x05\x12\x3d\xbf\x22\xad\x01\x00\x00")
+
+func migrations1792668856_call_optionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792668856_call_optionsDownSql,
+		"migrations/1792668856_call_options.down.sql",
+	)
+}
+
+func migrations1792668856_call_optionsDownSql() (*asset, error) {
+	bytes, err := migrations1792668856_call_optionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792668856_call_options.down.sql", size: 429, mode: os.FileMode(420), modTime: time.Unix(1792668856, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792668856_call_optionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\xd1\x31\x0b\xc2\x30\x10\x05\xe0\xdd\x5f\x71\x9b\xab\x83\xb8\x38\xa5\x36\x8a\x10\x5b\xd0\x14\xdc\x42\x68\x4f\x3d\x6c\xda\x92\x9c\xad\xfa\xeb\xcd\x2e\x88\x05\x3d\x78\xe3\xfb\xde\x70\x42\x69\xb9\x07\x2d\x12\x25\x21\x20\x33\x35\xe7\x30\x81\x78\x22\x4d\x61\x95\xab\x62\x97\x41\x69\xeb\xda\x94\xad\xeb\x3c\x86\x40\x6d\x03\x5a\x1e\x35\x64\x79\x4c\xa1\x14\xa4\x72\x2d\x0a\xa5\x61\x4a\x15\x36\x4c\xfc\x98\x2e\x27\xe2\x5b\xd6\xd9\xbb\xf1\x58\xf6\xc6\x85\xb3\x09\xf4\x44\xd8\x66\x5a\x6e\x62\xf9\xcd\x5f\xcc\x47\xba\x01\x9b\xea\x0b\x77\x36\x82\xbd\x22\x76\xb6\xa6\x1e\x0d\x93\xfb\x8b\xd9\xde\xf8\xd7\x6c\x87\xde\x11\x9b\x81\xf8\x12\x75\x13\xd8\xa3\x75\x90\xe4\xb9\x7a\x5f\x58\x0b\x75\x90\x23\x56\x06\x1b\xe5\x53\xeb\xe3\x13\x6d\xf5\xf8\x8c\xbe\x00\x43\xa2\x38\x90\x6c\x02\x00\x00")
+
+func migrations1792668856_call_optionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792668856_call_optionsUpSql,
+		"migrations/1792668856_call_options.up.sql",
+	)
+}
+
+func migrations1792668856_call_optionsUpSql() (*asset, error) {
+	bytes, err := migrations1792668856_call_optionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792668856_call_options.up.sql", size: 620, mode: os.FileMode(420), modTime: time.Unix(1792668856, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792755256_reconnectDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\xe6\x52\x00\x02\x97\x20\xff\x00\x05\x67\x7f\x9f\x50\x5f\x3f\x85\xc4\xd2\x92\xfc\xf8\xa2\xd4\xe4\xfc\xbc\xbc\xd4\xe4\x12\x6b\x2e\x47\x62\x34\xc1\xd5\xc7\x27\x25\x16\xa7\xc6\xa7\xa4\xe6\x24\x56\x92\xac\x35\x37\xb1\x02\xa6\x13\x00\x10\x19\xf2\x1b\xaa\x00\x00\x00")
+
+func migrations1792755256_reconnectDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792755256_reconnectDownSql,
+		"migrations/1792755256_reconnect.down.sql",
+	)
+}
+
+func migrations1792755256_reconnectDownSql() (*asset, error) {
+	bytes, err := migrations1792755256_reconnectDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792755256_reconnect.down.sql", size: 170, mode: os.FileMode(420), modTime: time.Unix(1792755256, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations1792755256_reconnectUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\xcc\x31\x0a\x02\x31\x10\x46\xe1\x3e\xa7\xf8\x8f\xa0\xb6\x5b\xcd\x9a\x51\x84\x31\x81\x65\x52\x87\xb8\x06\x11\x34\x0b\x26\x82\xde\xde\xad\x6c\x2c\xd4\xd7\xbf\x8f\x44\x79\x80\x52\x2f\x8c\x9a\x5b\x3b\x97\x53\x35\x98\x23\x6b\xb1\xf6\x12\xf6\x0e\xe9\xde\xa6\x78\xcb\xe3\x54\x4a\x1e\x1b\x7a\xef\x05\xce\x2b\x5c\x10\x81\xe5\x0d\x05\x51\xe8\x10\xb8\x33\xf4\x03\xf7\x96\xe2\x21\xd5\x1c\x8f\xf9\x92\x9e\xd8\x39\xe5\xed\xbc\x7e\xb8\xcb\x7f\xd1\x6b\x7a\x7c\x35\x57\x8b\xce\xbc\x00\xe1\x1f\xdf\xfe\xfa\x00\x00\x00")
+
+func migrations1792755256_reconnectUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations1792755256_reconnectUpSql,
+		"migrations/1792755256_reconnect.up.sql",
+	)
+}
+
+func migrations1792755256_reconnectUpSql() (*asset, error) {
+	bytes, err := migrations1792755256_reconnectUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/1792755256_reconnect.up.sql", size: 250, mode: os.FileMode(420), modTime: time.Unix(1792755256, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -296,27 +548,37 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"migrations/1633685677_init.down.sql":     migrations1633685677_initDownSql,
-	"migrations/1633685677_init.up.sql":       migrations1633685677_initUpSql,
-	"migrations/1668845636_settings.down.sql": migrations1668845636_settingsDownSql,
-	"migrations/1668845636_settings.up.sql":   migrations1668845636_settingsUpSql,
-	"migrations/1677165148_k8s.down.sql":      migrations1677165148_k8sDownSql,
-	"migrations/1677165148_k8s.up.sql":        migrations1677165148_k8sUpSql,
-	"migrations/1714890607_settings.down.sql": migrations1714890607_settingsDownSql,
-	"migrations/1714890607_settings.up.sql":   migrations1714890607_settingsUpSql,
+	"migrations/1633685677_init.down.sql":         migrations1633685677_initDownSql,
+	"migrations/1633685677_init.up.sql":           migrations1633685677_initUpSql,
+	"migrations/1668845636_settings.down.sql":     migrations1668845636_settingsDownSql,
+	"migrations/1668845636_settings.up.sql":       migrations1668845636_settingsUpSql,
+	"migrations/1677165148_k8s.down.sql":          migrations1677165148_k8sDownSql,
+	"migrations/1677165148_k8s.up.sql":            migrations1677165148_k8sUpSql,
+	"migrations/1714890607_settings.down.sql":     migrations1714890607_settingsDownSql,
+	"migrations/1714890607_settings.up.sql":       migrations1714890607_settingsUpSql,
+	"migrations/1792323256_environments.down.sql": migrations1792323256_environmentsDownSql,
+	"migrations/1792323256_environments.up.sql":   migrations1792323256_environmentsUpSql,
+	"migrations/1792409656_history.down.sql":      migrations1792409656_historyDownSql,
+	"migrations/1792409656_history.up.sql":        migrations1792409656_historyUpSql,
+	"migrations/1792496056_proxy.down.sql":        migrations1792496056_proxyDownSql,
+	"migrations/1792496056_proxy.up.sql":          migrations1792496056_proxyUpSql,
+	"migrations/1792582456_cert_expiry.down.sql":  migrations1792582456_cert_expiryDownSql,
+	"migrations/1792582456_cert_expiry.up.sql":    migrations1792582456_cert_expiryUpSql,
+	"migrations/1792668856_call_options.down.sql": migrations1792668856_call_optionsDownSql,
+	"migrations/1792668856_call_options.up.sql":   migrations1792668856_call_optionsUpSql,
+	"migrations/1792755256_reconnect.down.sql":    migrations1792755256_reconnectDownSql,
+	"migrations/1792755256_reconnect.up.sql":      migrations1792755256_reconnectUpSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//
-//	data/
-//	  foo.txt
-//	  img/
-//	    a.png
-//	    b.png
-//
+//     data/
+//       foo.txt
+//       img/
+//         a.png
+//         b.png
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -350,14 +612,26 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"migrations": &bintree{nil, map[string]*bintree{
-		"1633685677_init.down.sql":     &bintree{migrations1633685677_initDownSql, map[string]*bintree{}},
-		"1633685677_init.up.sql":       &bintree{migrations1633685677_initUpSql, map[string]*bintree{}},
-		"1668845636_settings.down.sql": &bintree{migrations1668845636_settingsDownSql, map[string]*bintree{}},
-		"1668845636_settings.up.sql":   &bintree{migrations1668845636_settingsUpSql, map[string]*bintree{}},
-		"1677165148_k8s.down.sql":      &bintree{migrations1677165148_k8sDownSql, map[string]*bintree{}},
-		"1677165148_k8s.up.sql":        &bintree{migrations1677165148_k8sUpSql, map[string]*bintree{}},
-		"1714890607_settings.down.sql": &bintree{migrations1714890607_settingsDownSql, map[string]*bintree{}},
-		"1714890607_settings.up.sql":   &bintree{migrations1714890607_settingsUpSql, map[string]*bintree{}},
+		"1633685677_init.down.sql":         &bintree{migrations1633685677_initDownSql, map[string]*bintree{}},
+		"1633685677_init.up.sql":           &bintree{migrations1633685677_initUpSql, map[string]*bintree{}},
+		"1668845636_settings.down.sql":     &bintree{migrations1668845636_settingsDownSql, map[string]*bintree{}},
+		"1668845636_settings.up.sql":       &bintree{migrations1668845636_settingsUpSql, map[string]*bintree{}},
+		"1677165148_k8s.down.sql":          &bintree{migrations1677165148_k8sDownSql, map[string]*bintree{}},
+		"1677165148_k8s.up.sql":            &bintree{migrations1677165148_k8sUpSql, map[string]*bintree{}},
+		"1714890607_settings.down.sql":     &bintree{migrations1714890607_settingsDownSql, map[string]*bintree{}},
+		"1714890607_settings.up.sql":       &bintree{migrations1714890607_settingsUpSql, map[string]*bintree{}},
+		"1792323256_environments.down.sql": &bintree{migrations1792323256_environmentsDownSql, map[string]*bintree{}},
+		"1792323256_environments.up.sql":   &bintree{migrations1792323256_environmentsUpSql, map[string]*bintree{}},
+		"1792409656_history.down.sql":      &bintree{migrations1792409656_historyDownSql, map[string]*bintree{}},
+		"1792409656_history.up.sql":        &bintree{migrations1792409656_historyUpSql, map[string]*bintree{}},
+		"1792496056_proxy.down.sql":        &bintree{migrations1792496056_proxyDownSql, map[string]*bintree{}},
+		"1792496056_proxy.up.sql":          &bintree{migrations1792496056_proxyUpSql, map[string]*bintree{}},
+		"1792582456_cert_expiry.down.sql":  &bintree{migrations1792582456_cert_expiryDownSql, map[string]*bintree{}},
+		"1792582456_cert_expiry.up.sql":    &bintree{migrations1792582456_cert_expiryUpSql, map[string]*bintree{}},
+		"1792668856_call_options.down.sql": &bintree{migrations1792668856_call_optionsDownSql, map[string]*bintree{}},
+		"1792668856_call_options.up.sql":   &bintree{migrations1792668856_call_optionsUpSql, map[string]*bintree{}},
+		"1792755256_reconnect.down.sql":    &bintree{migrations1792755256_reconnectDownSql, map[string]*bintree{}},
+		"1792755256_reconnect.up.sql":      &bintree{migrations1792755256_reconnectUpSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -0,0 +1,170 @@
+// Package certgen generates the development CA and the server and client certificates signed by it.
+package certgen
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// key types.
+const (
+	KeyTypeRSA   = "rsa"
+	KeyTypeECDSA = "ecdsa"
+)
+
+const (
+	rsaKeySize   = 2048
+	organization = "Warthog development"
+)
+
+// Options certificates options.
+type Options struct {
+	// KeyType rsa or ecdsa (P-256).
+	KeyType string
+	// Hosts DNS names and IP addresses of the server certificate.
+	Hosts []string
+	// Validity of the certificates.
+	Validity time.Duration
+}
+
+// KeyPair PEM encoded certificate and private key.
+type KeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// Bundle CA and the certificates signed by it.
+type Bundle struct {
+	CA     *KeyPair
+	Server *KeyPair
+	Client *KeyPair
+}
+
+type signer struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// Generate creates the CA, the server certificate for the hosts and the client certificate.
+func Generate(opts *Options) (*Bundle, error) {
+	if len(opts.Hosts) == 0 {
+		return nil, errors.New("no server hosts")
+	}
+	if opts.Validity <= 0 {
+		return nil, fmt.Errorf("wrong validity: %s", opts.Validity)
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(opts.Validity)
+
+	ca := &x509.Certificate{
+		Subject:               pkix.Name{Organization: []string{organization}, CommonName: "Warthog development CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	caPair, caSigner, err := create(ca, nil, opts.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA: %v", err)
+	}
+
+	server := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{organization}, CommonName: opts.Hosts[0]},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range opts.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			server.IPAddresses = append(server.IPAddresses, ip)
+		} else {
+			server.DNSNames = append(server.DNSNames, h)
+		}
+	}
+	serverPair, _, err := create(server, caSigner, opts.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server certificate: %v", err)
+	}
+
+	client := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{organization}, CommonName: "Warthog client"},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientPair, _, err := create(client, caSigner, opts.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client certificate: %v", err)
+	}
+
+	return &Bundle{
+		CA:     caPair,
+		Server: serverPair,
+		Client: clientPair,
+	}, nil
+}
+
+// create generates the key and the certificate signed by the parent, nil parent creates the self-signed certificate.
+func create(tmpl *x509.Certificate, parent *signer, keyType string) (*KeyPair, *signer, error) {
+	key, err := generateKey(keyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tmpl.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := key.(*rsa.PrivateKey); !ok {
+		// ECDSA keys are not used for the key encipherment
+		tmpl.KeyUsage &^= x509.KeyUsageKeyEncipherment
+	}
+
+	if parent == nil {
+		parent = &signer{cert: tmpl, key: key}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent.cert, key.Public(), parent.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &KeyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, &signer{cert: cert, key: key}, nil
+}
+
+func generateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case KeyTypeECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	return nil, fmt.Errorf("unknown key type: %s", keyType)
+}
@@ -0,0 +1,53 @@
+export {
+    setCallOptions,
+    getCallOptions,
+}
+
+import {isNull} from "./index.js";
+
+const callNumbers = ["max-recv-msg-size", "max-send-msg-size", "keepalive-time", "keepalive-timeout"];
+const callFlags = ["keepalive-permit-without-stream", "wait-for-ready"];
+
+function setCallOptions(prefix, opts) {
+    if (isNull(opts)) {
+        opts = {};
+    }
+
+    $("#" + prefix + "-compression").val(isNull(opts.compression) ? "" : opts.compression);
+    for (const name of callNumbers) {
+        let value = opts[name.replaceAll("-", "_")];
+        $("#" + prefix + "-" + name).val(isNull(value) || value === 0 ? "" : value);
+    }
+    for (const name of callFlags) {
+        let value = opts[name.replaceAll("-", "_")];
+        let input = $("#" + prefix + "-" + name);
+        if (input.is(":checkbox")) {
+            input.prop("checked", value === true);
+        } else {
+            input.val(isNull(value) ? "" : String(value));
+        }
+    }
+}
+
+function getCallOptions(prefix) {
+    let opts = {
+        compression: $("#" + prefix + "-compression").val().trim(),
+    };
+
+    for (const name of callNumbers) {
+        let value = parseInt($("#" + prefix + "-" + name).val(), 10);
+        if (!isNaN(value)) {
+            opts[name.replaceAll("-", "_")] = value;
+        }
+    }
+    for (const name of callFlags) {
+        let input = $("#" + prefix + "-" + name);
+        if (input.is(":checkbox")) {
+            opts[name.replaceAll("-", "_")] = input.is(":checked");
+        } else if (input.val() !== "") {
+            opts[name.replaceAll("-", "_")] = input.val() === "true";
+        }
+    }
+
+    return opts;
+}
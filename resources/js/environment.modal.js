@@ -0,0 +1,135 @@
+export {initEnvironmentModal, showEnvironmentModal, loadEnvironments};
+
+import {isNull, showModalError} from "./index.js";
+
+const newEnvironmentID = 0;
+
+let environments = [];
+
+function initEnvironmentModal() {
+    $("#environment-modal-list").change(function () {
+        showEnvironment(parseInt($(this).val(), 10));
+    });
+
+    $("#environment-modal-submit").click(function (event) {
+        let form = $("#environment-modal-form")[0];
+        if (!form.checkValidity()) {
+            event.preventDefault();
+            event.stopPropagation();
+        } else {
+            saveEnvironment();
+        }
+        form.classList.add("was-validated");
+    });
+
+    $("#environment-modal-delete").click(function () {
+        deleteEnvironment();
+    });
+
+    $("#environmentModal").on("hidden.bs.modal", function () {
+        $(this).find("form").removeClass("was-validated");
+    });
+}
+
+function showEnvironmentModal() {
+    loadEnvironments(function () {
+        fillEnvironments(newEnvironmentID);
+        $("#environmentModal").modal("show");
+    });
+}
+
+function loadEnvironments(callback) {
+    astilectron.sendMessage({name: "environment.get"}, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        environments = isNull(message.payload.data) ? [] : message.payload.data;
+        if (callback !== undefined) {
+            callback(environments);
+        }
+    });
+}
+
+function fillEnvironments(selectedID) {
+    let sel = $("#environment-modal-list");
+    sel.empty();
+    sel.append($("<option>", {value: newEnvironmentID, text: "New environment"}));
+    for (const env of environments) {
+        sel.append($("<option>", {value: env.id, text: env.name}));
+    }
+    sel.val(selectedID);
+    showEnvironment(selectedID);
+}
+
+function showEnvironment(id) {
+    let env = environments.find((e) => e.id === id);
+    if (env === undefined) {
+        $("#environment-modal-name").val("");
+        $("#environment-modal-variables").val("");
+        $("#environment-modal-delete").prop("disabled", true);
+        return;
+    }
+
+    let lines = [];
+    for (const name of Object.keys(env.variables).sort()) {
+        lines.push(name + "=" + env.variables[name]);
+    }
+    $("#environment-modal-name").val(env.name);
+    $("#environment-modal-variables").val(lines.join("\n"));
+    $("#environment-modal-delete").prop("disabled", false);
+}
+
+function getVariables() {
+    let variables = {};
+    for (const line of $("#environment-modal-variables").val().split("\n")) {
+        let pos = line.indexOf("=");
+        if (pos <= 0) {
+            continue;
+        }
+        variables[line.substring(0, pos).trim()] = line.substring(pos + 1);
+    }
+    return variables;
+}
+
+function saveEnvironment() {
+    let id = parseInt($("#environment-modal-list").val(), 10);
+    let name = $("#environment-modal-name").val();
+    let req = {
+        name: "environment.create",
+        payload: {
+            name: name,
+            variables: getVariables(),
+        },
+    };
+    if (id !== newEnvironmentID) {
+        req.name = "environment.update";
+        req.payload.id = id;
+    }
+
+    astilectron.sendMessage(req, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        environments = message.payload.data;
+        let env = environments.find((e) => e.name === name.trim());
+        fillEnvironments(env === undefined ? newEnvironmentID : env.id);
+    });
+}
+
+function deleteEnvironment() {
+    let id = parseInt($("#environment-modal-list").val(), 10);
+    if (id === newEnvironmentID) {
+        return;
+    }
+
+    astilectron.sendMessage({name: "environment.delete", payload: {id: id}}, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        environments = message.payload.data;
+        fillEnvironments(newEnvironmentID);
+    });
+}
@@ -0,0 +1,97 @@
+export {initGrpcurlModal, showQueryExport, showGrpcurlImport};
+
+import {isNull, showModalError} from "./index.js";
+import {currentMethod, currentServer, currentService, loadServer} from "./server.js";
+import {getQueryPayload} from "./request.js";
+import {showTree} from "./tree.js";
+
+let files = undefined;
+
+function initGrpcurlModal() {
+    $("#grpcurl-modal-copy").click(function () {
+        const {clipboard} = require("electron");
+        clipboard.writeText($("#grpcurl-modal-command").val());
+    });
+
+    $("#grpcurl-modal-save-files").click(function () {
+        saveFiles();
+    });
+
+    $("#grpcurl-modal-import").click(function () {
+        importCommand();
+    });
+}
+
+const exportTitles = {
+    grpcurl: "grpcurl command",
+    go: "Go client code",
+};
+
+function showQueryExport(format) {
+    if (isNull(currentServer) || isNull(currentService) || isNull(currentMethod)) {
+        return;
+    }
+
+    astilectron.sendMessage({name: "query.export." + format, payload: getQueryPayload()}, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        let data = message.payload.data;
+        files = data.files;
+        showModal(exportTitles[format], false, isNull(data.code) ? data.command : data.code, data.warnings);
+    });
+}
+
+function showGrpcurlImport() {
+    files = undefined;
+    showModal("Import grpcurl command", true, "", []);
+}
+
+function showModal(title, isImport, command, warnings) {
+    $("#grpcurlModalLabel").text(title);
+    $("#grpcurl-modal-command").val(command).prop("readonly", !isImport);
+    $("#grpcurl-modal-import-help").toggle(isImport);
+    $("#grpcurl-modal-import").toggle(isImport);
+    $("#grpcurl-modal-copy").toggle(!isImport);
+    $("#grpcurl-modal-save-files").toggle(!isImport && !isNull(files));
+
+    let list = $("#grpcurl-modal-warnings").empty();
+    for (const w of isNull(warnings) ? [] : warnings) {
+        list.append($("<li>").text(w));
+    }
+
+    $("#grpcurlModal").modal("show");
+}
+
+function saveFiles() {
+    const {dialog} = require("electron").remote;
+    let path = dialog.showOpenDialogSync({
+        properties: ["openDirectory", "createDirectory"],
+    });
+    if (path === undefined || isNull(files)) {
+        return;
+    }
+
+    const fs = require("fs");
+    for (const [name, content] of Object.entries(files)) {
+        fs.writeFileSync(require("path").join(path[0], name), content);
+    }
+}
+
+function importCommand() {
+    let req = {
+        name: "workspace.import.grpcurl",
+        payload: {command: $("#grpcurl-modal-command").val()},
+    };
+
+    astilectron.sendMessage(req, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        $("#grpcurlModal").modal("hide");
+        showTree(message.payload.data.tree);
+        loadServer(message.payload.data.query);
+    });
+}
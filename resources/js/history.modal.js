@@ -0,0 +1,222 @@
+export {initHistoryModal, showHistoryModal};
+
+import {isNull, showModalError} from "./index.js";
+import {currentMethod, currentServer, currentService, loadServer} from "./server.js";
+import {hideQueryError, showQueryError} from "./request.js";
+import {showTree} from "./tree.js";
+
+let history = [];
+let selected = undefined;
+let searchTimer = undefined;
+
+function initHistoryModal() {
+    $("#history-modal-search").on("input", function () {
+        clearTimeout(searchTimer);
+        searchTimer = setTimeout(loadHistory, 300);
+    });
+
+    $("#history-modal-status, #history-modal-current-server").change(function () {
+        loadHistory();
+    });
+
+    $("#history-modal-run").click(function () {
+        runHistory();
+    });
+
+    $("#history-modal-save").click(function () {
+        saveHistory();
+    });
+
+    $("#history-modal-delete").click(function () {
+        deleteHistory();
+    });
+
+    $("#history-modal-clear").click(function () {
+        clearHistory();
+    });
+}
+
+function showHistoryModal() {
+    $("#history-modal-current-server")
+        .prop("disabled", isNull(currentServer))
+        .prop("checked", !isNull(currentServer));
+    loadHistory();
+    $("#historyModal").modal("show");
+}
+
+function getFilter() {
+    let filter = {
+        search: $("#history-modal-search").val(),
+        status: $("#history-modal-status").val(),
+    };
+    if ($("#history-modal-current-server").is(":checked") && !isNull(currentServer)) {
+        filter.server_id = currentServer.id;
+    }
+    return filter;
+}
+
+function loadHistory() {
+    astilectron.sendMessage({name: "history.get", payload: getFilter()}, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        history = isNull(message.payload.data) ? [] : message.payload.data;
+        fillHistory();
+    });
+}
+
+function fillHistory() {
+    let list = $("#history-modal-list").empty();
+    let status = $("#history-modal-status");
+    let statuses = new Set(status.find("option").map(function () {
+        return $(this).val();
+    }).get());
+
+    for (const h of history) {
+        let item = $("<a>", {href: "#", class: "list-group-item list-group-item-action"})
+            .attr("data-history-id", h.id)
+            .append($("<div>").text(h.service + "." + h.method))
+            .append(
+                $("<small>", {class: "text-muted"}).text(
+                    new Date(h.created_at).toLocaleString() + " · " + h.server_title + " · " + h.status
+                )
+            );
+        item.click(function (e) {
+            e.preventDefault();
+            list.find(".active").removeClass("active");
+            $(this).addClass("active");
+            showHistory(h);
+        });
+        list.append(item);
+
+        if (!statuses.has(h.status)) {
+            statuses.add(h.status);
+            status.append($("<option>", {value: h.status, text: h.status}));
+        }
+    }
+
+    showHistory(undefined);
+}
+
+function showHistory(h) {
+    selected = h;
+    $("#history-modal-run, #history-modal-save, #history-modal-delete").prop("disabled", isNull(h));
+
+    let details = $("#history-modal-details");
+    if (isNull(h)) {
+        details.hide();
+        return;
+    }
+
+    details.find(".history-title").text(h.service + "." + h.method);
+    details
+        .find(".history-status")
+        .text(h.code + ": " + h.status)
+        .removeClass("bg-success bg-danger")
+        .addClass(h.code === 0 ? "bg-success" : "bg-danger");
+    details.find(".history-time").text(h.spent_time);
+    details.find(".history-request").text(
+        JSON.stringify({data: h.data, metadata: h.metadata}, null, 1)
+    );
+    details.find(".history-response").text(
+        h.code === 0 ? h.response : h.message
+    );
+    $("#history-modal-query-title").val(h.method);
+    details.show();
+}
+
+function runHistory() {
+    if (isNull(selected)) {
+        return;
+    }
+
+    let id = selected.id;
+    let run = function () {
+        hideQueryError();
+        astilectron.sendMessage({name: "history.run", payload: {id: id}}, function (message) {
+            if (message.payload.status !== "ok") {
+                showQueryError(message.payload.error);
+            }
+        });
+    };
+
+    $("#historyModal").modal("hide");
+
+    if (
+        !isNull(currentServer) &&
+        currentServer.id === selected.server_id &&
+        !isNull(currentService) &&
+        currentService.name === selected.service &&
+        !isNull(currentMethod) &&
+        currentMethod.name === selected.method
+    ) {
+        run();
+        return;
+    }
+
+    loadServer(
+        {id: selected.server_id},
+        {service: {name: selected.service}, method: {name: selected.method}},
+        run
+    );
+}
+
+function saveHistory() {
+    if (isNull(selected)) {
+        return;
+    }
+
+    let req = {
+        name: "history.save",
+        payload: {
+            id: selected.id,
+            title: $("#history-modal-query-title").val(),
+        },
+    };
+
+    astilectron.sendMessage(req, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        showTree(message.payload.data.tree);
+        $("#historyModal").modal("hide");
+    });
+}
+
+function deleteHistory() {
+    if (isNull(selected)) {
+        return;
+    }
+
+    astilectron.sendMessage({name: "history.delete", payload: {id: selected.id}}, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        loadHistory();
+    });
+}
+
+function clearHistory() {
+    const {dialog} = require("electron").remote;
+    let filter = getFilter();
+    let answer = dialog.showMessageBoxSync({
+        type: "question",
+        message: isNull(filter.server_id) ? "Clear the whole history?" : "Clear the history of the current workspace?",
+        buttons: ["Clear", "Cancel"],
+        cancelId: 1,
+    });
+    if (answer !== 0) {
+        return;
+    }
+
+    astilectron.sendMessage({name: "history.clear", payload: {server_id: filter.server_id}}, function (message) {
+        if (message.payload.status !== "ok") {
+            showModalError(message);
+            return;
+        }
+        loadHistory();
+    });
+}
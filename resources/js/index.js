@@ -6,6 +6,7 @@ export {
     isNull,
     setCurrentSettings,
     loadFile,
+    showModalError,
 };
 
 import {saveQuery} from "./query.js";
@@ -22,10 +23,16 @@ import {
     currentServices,
     loadServer,
     saveRequest,
+    schemaChanged,
     setCurrentQuery,
     setRequestTitle,
+    showConnectionState,
 } from "./server.js";
-import {hideStreamControl, initStreamControl, query, response, showQueryError,} from "./request.js";
+import {hideStreamControl, initStreamControl, query, response, showQueryError, showVariables,} from "./request.js";
+import {workspaceExport, workspaceImport} from "./workspace.export.js";
+import {initEnvironmentModal, showEnvironmentModal} from "./environment.modal.js";
+import {initHistoryModal, showHistoryModal} from "./history.modal.js";
+import {initGrpcurlModal, showGrpcurlImport, showQueryExport} from "./grpcurl.modal.js";
 
 let currentSettings = undefined;
 let treeRootNodes = new Set();
@@ -49,6 +56,21 @@ $(document).ready(function () {
                 case "menu.about":
                     showAbout(message.payload);
                     break;
+                case "menu.environments":
+                    showEnvironmentModal();
+                    break;
+                case "menu.history":
+                    showHistoryModal();
+                    break;
+                case "menu.workspace.export":
+                    workspaceExport();
+                    break;
+                case "menu.workspace.import":
+                    workspaceImport();
+                    break;
+                case "menu.grpcurl.import":
+                    showGrpcurlImport();
+                    break;
                 case "query.response":
                     response(message.payload);
                     break;
@@ -58,6 +80,12 @@ $(document).ready(function () {
                 case "message.error":
                     showQueryError(message.payload)
                     break;
+                case "connection.state":
+                    showConnectionState(message.payload);
+                    break;
+                case "server.schema.changed":
+                    schemaChanged(message.payload);
+                    break;
                 case "check.updates":
                     showUpdates(message.payload);
                     break;
@@ -92,6 +120,15 @@ $(document).ready(function () {
                 case "modal.settings.html":
                     initSettingsModal();
                     break;
+                case "modal.environment.html":
+                    initEnvironmentModal();
+                    break;
+                case "modal.history.html":
+                    initHistoryModal();
+                    break;
+                case "modal.grpcurl.html":
+                    initGrpcurlModal();
+                    break;
             }
         });
     });
@@ -131,6 +168,22 @@ $(document).ready(function () {
         editServer(currentServer);
     });
 
+    $("#export-grpcurl").click(function () {
+        showQueryExport("grpcurl");
+    });
+
+    $("#export-go").click(function () {
+        showQueryExport("go");
+    });
+
+    $("#request-variables-clear").click(function () {
+        astilectron.sendMessage({name: "variables.clear"}, function (message) {
+            if (message.payload.status === "ok") {
+                showVariables(message.payload.data, null);
+            }
+        });
+    });
+
     let navRequest = $("#nav-request form");
     navRequest.submit(function () {
         query();
@@ -345,6 +398,12 @@ function showAbout(data) {
     modal.modal("show");
 }
 
+function showModalError(message) {
+    let modal = $("#errorModal");
+    modal.find(".error-description").text(message.payload.error.message);
+    modal.modal("show");
+}
+
 function showUpdates(data) {
     let modal = $("#updatesModal");
     if (!isNull(data)) {
@@ -1,5 +1,5 @@
 import {currentMethod, currentQuery, currentServer, currentService, setCurrentQuery, setCurrentServer, setRequestTitle,} from "./server.js";
-import {getRequestData, getRequestMetadata} from "./request.js";
+import {getRequestAssertions, getRequestCompression, getRequestData, getRequestExtract, getRequestMetadata} from "./request.js";
 import {isNull} from "./index.js";
 
 export {saveQuery};
@@ -35,6 +35,9 @@ function saveQuery() {
             request: {
                 input: request,
                 metadata: metadata,
+                extract: getRequestExtract(),
+                assertions: getRequestAssertions(),
+                compression: getRequestCompression(),
             },
         },
     };
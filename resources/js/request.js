@@ -4,9 +4,15 @@ export {
     hideStreamControl,
     query,
     response,
+    restoreSession,
     getRequestData,
+    getQueryPayload,
     getRequestMetadata,
+    getRequestExtract,
+    getRequestAssertions,
+    getRequestCompression,
     showQueryError,
+    showVariables,
 };
 import {currentMethod, currentQuery, currentServer, currentService, protoTypeBool, protoTypeBytes, protoTypeEnum, protoTypeMessage, saveRequest,} from "./server.js";
 import {isNull} from "./index.js";
@@ -17,48 +23,45 @@ const MethodTypeClientStream = "cs";
 const MethodTypeServerStream = "ss";
 const MethodTypeBidiStream = "css";
 
-let streamStopped = false;
+// running sessions by the server, service and method
+let sessions = new Map();
+// sessions finished before the session ID was received
+let finishedSessions = new Set();
 
 function query() {
     if (currentService === undefined || currentMethod === undefined) {
         return;
     }
 
+    let key = getSessionKey(currentServer.id, currentService.name, currentMethod.name);
+    let session = sessions.get(key);
+
     if (isQueryRun()) {
-        astilectron.sendMessage({name: "query.cancel"}, function () {
-        });
+        if (!isNull(session)) {
+            astilectron.sendMessage({name: "query.cancel", payload: {session_id: session.id}}, function () {
+            });
+        }
         setQueryRunButton();
         return;
     }
 
     hideQueryError();
-
-    let request = {};
-    if (currentMethod.input !== undefined) {
-        for (const field of currentMethod.input) {
-            let data = getRequestData(field, undefined, false);
-            $.extend(request, data);
-        }
-    }
-
-    console.log("request: " + JSON.stringify(request, null, 1));
+    showAssertions(null);
 
     let req = {
         name: "query.run",
-        payload: {
-            server_id: currentServer.id,
-            service: currentService.name,
-            method: currentMethod.name,
-            metadata: getRequestMetadata(),
-            data: request,
-        },
+        payload: getQueryPayload(),
     };
 
-    if (currentMethod.type === MethodTypeUnary) {
+    console.log("request: " + JSON.stringify(req.payload.data, null, 1));
+
+    let methodType = currentMethod.type;
+    if (methodType === MethodTypeUnary) {
         setQueryCancelButton();
+    } else if (isClientStream(methodType) && !isNull(session)) {
+        req.payload.session_id = session.id;
     } else {
         showStreamControl();
-        streamStopped = false;
     }
 
     if (isNull(currentQuery)) {
@@ -66,29 +69,75 @@ function query() {
     }
 
     astilectron.sendMessage(req, function (message) {
-        if (currentMethod.type === MethodTypeUnary) {
-            setQueryRunButton();
-        }
+        let isCurrent = !isNull(currentServer) && !isNull(currentMethod) &&
+            key === getSessionKey(currentServer.id, currentService.name, currentMethod.name);
         if (message.payload.status !== "ok") {
-            showQueryError(message.payload.error);
+            if (isCurrent) {
+                if (methodType === MethodTypeUnary) {
+                    setQueryRunButton();
+                }
+                showQueryError(message.payload.error);
+            }
             return
         }
-        $("#stream-sent .sent").html(message.payload.data.sent);
+        let data = message.payload.data;
+        if (!finishedSessions.delete(data.session_id) && !sessions.has(key)) {
+            sessions.set(key, {id: data.session_id, type: methodType, stopped: false});
+        }
+        if (isCurrent) {
+            $("#stream-sent .sent").html(data.sent);
+        }
     });
 }
 
+// restoreSession shows the controls of the running session of the current method.
+function restoreSession() {
+    let session = sessions.get(getSessionKey(currentServer.id, currentService.name, currentMethod.name));
+    if (isNull(session)) {
+        setQueryRunButton();
+        return;
+    }
+
+    if (session.type === MethodTypeUnary) {
+        setQueryCancelButton();
+    } else {
+        showStreamControl();
+    }
+}
+
+function getCurrentSession() {
+    if (isNull(currentServer) || isNull(currentService) || isNull(currentMethod)) {
+        return undefined;
+    }
+    return sessions.get(getSessionKey(currentServer.id, currentService.name, currentMethod.name));
+}
+
+function getSessionKey(serverID, service, method) {
+    return serverID + "/" + service + "/" + method;
+}
+
+function isClientStream(methodType) {
+    return methodType === MethodTypeClientStream || methodType === MethodTypeBidiStream;
+}
+
 function initStreamControl() {
     $("#stream-stop").on("click", function () {
-        streamStopped = true;
-        astilectron.sendMessage({name: "query.close.stream"}, function () {
-        });
+        let session = getCurrentSession();
+        if (!isNull(session)) {
+            session.stopped = true;
+            astilectron.sendMessage({name: "query.close.stream", payload: {session_id: session.id}}, function () {
+            });
+        }
         hideStreamControl();
     });
 
     $("#stream-cancel").on("click", function () {
-        streamStopped = true;
-        astilectron.sendMessage({name: "query.cancel"}, function () {
-        });
+        let session = getCurrentSession();
+        if (!isNull(session)) {
+            session.stopped = true;
+            astilectron.sendMessage({name: "query.cancel", payload: {session_id: session.id}}, function () {
+            });
+        }
         hideStreamControl();
     });
 }
@@ -112,6 +161,7 @@ function showStreamControl() {
     $("#stream-info").show();
     $("#stream-control").css("visibility", "visible");
     $("#time-spent").html("");
+    $("#query-attempts").html("");
 }
 
 function hideStreamControl() {
@@ -122,6 +172,31 @@ function hideStreamControl() {
 }
 
 function response(data) {
+    let key = getSessionKey(data.server_id, data.service, data.method);
+    let session = sessions.get(key);
+    let streamStopped = !isNull(session) && session.id === data.session_id && session.stopped;
+    if (data.last) {
+        if (!isNull(session) && session.id === data.session_id) {
+            sessions.delete(key);
+        } else {
+            finishedSessions.add(data.session_id);
+        }
+    }
+
+    // the sessions of other methods keep running in the background
+    if (isNull(currentServer) || isNull(currentMethod) ||
+        key !== getSessionKey(currentServer.id, currentService.name, currentMethod.name)) {
+        return;
+    }
+
+    if (data.last) {
+        if (currentMethod.type === MethodTypeUnary) {
+            setQueryRunButton();
+        } else {
+            hideStreamControl();
+        }
+    }
+
     if (isNull(data.error)) {
         $("#stream-received .received").html(data.received);
         $("#query-error").html("");
@@ -141,7 +216,6 @@ function response(data) {
             } else {
                 $("#query-result").html("the server returned an empty response");
             }
-            streamStopped = false;
         } else if (data.json_string !== "") {
             let sep = '<div class="hr"><span>' + data.time + "</span></div>";
             if (data.received > 1) {
@@ -171,6 +245,24 @@ function response(data) {
     }
 
     showHeadersTrailers(data.header, data.trailer);
+    showAssertions(data.assertions);
+    showAttempts(data.attempts);
+
+    if (!isNull(data.variables) || !isNull(data.extract_error)) {
+        astilectron.sendMessage({name: "variables.get"}, function (message) {
+            if (message.payload.status === "ok") {
+                showVariables(message.payload.data, data.extract_error);
+            }
+        });
+    }
+}
+
+function showAttempts(attempts) {
+    if (isNull(attempts) || attempts <= 1) {
+        $("#query-attempts").html("");
+        return;
+    }
+    $("#query-attempts").html(attempts + " attempts");
 }
 
 function getRequestData(field, root, disableProtoFQN) {
@@ -323,6 +415,29 @@ function getProtoFQN(field, disableProtoFQN) {
     return field.proto_fqn;
 }
 
+function getQueryPayload() {
+    let request = {};
+    let input = {};
+    if (currentMethod.input !== undefined) {
+        for (const field of currentMethod.input) {
+            $.extend(request, getRequestData(field, undefined, false));
+            $.extend(input, getRequestData(field, undefined, true));
+        }
+    }
+
+    return {
+        server_id: currentServer.id,
+        service: currentService.name,
+        method: currentMethod.name,
+        metadata: getRequestMetadata(),
+        extract: getRequestExtract(),
+        assertions: getRequestAssertions(),
+        compression: getRequestCompression(),
+        data: request,
+        input: input,
+    };
+}
+
 function getRequestMetadata() {
     let metadata = {};
     let keys = [];
@@ -337,6 +452,75 @@ function getRequestMetadata() {
     return metadata;
 }
 
+function getRequestExtract() {
+    let rules = [];
+    for (let line of $("#request-extract").val().split("\n")) {
+        let idx = line.indexOf("=");
+        if (idx <= 0) {
+            continue;
+        }
+        let rule = {
+            variable: line.substring(0, idx).trim(),
+            source: "body",
+            path: line.substring(idx + 1).trim(),
+        };
+        for (const source of ["header", "trailer"]) {
+            if (rule.path.startsWith(source + ":")) {
+                rule.source = source;
+                rule.path = rule.path.substring(source.length + 1).trim();
+            }
+        }
+        if (rule.variable !== "") {
+            rules.push(rule);
+        }
+    }
+    return rules;
+}
+
+function getRequestCompression() {
+    return $("#request-compression").val().trim();
+}
+
+function getRequestAssertions() {
+    return $("#request-assertions")
+        .val()
+        .split("\n")
+        .map((line) => line.trim())
+        .filter((line) => line !== "")
+        .map((line) => ({expression: line}));
+}
+
+function showAssertions(assertions) {
+    let list = $("#query-assertions").html("");
+    if (isNull(assertions)) {
+        return;
+    }
+    for (const a of assertions) {
+        let item = $("<li></li>").text(" " + a.expression);
+        if (!a.passed) {
+            let reason = isNull(a.error) ? "actual: " + (isNull(a.actual) ? "" : a.actual) : a.error;
+            item.append($("<span class=\"text-muted\"></span>").text(" (" + reason + ")"));
+        }
+        item.prepend(
+            $("<i></i>").addClass(a.passed ? "bi bi-check-circle text-success" : "bi bi-x-circle text-danger")
+        );
+        list.append(item);
+    }
+}
+
+function showVariables(variables, err) {
+    if (isNull(err) || err === "") {
+        $("#request-extract-error").html("").hide();
+    } else {
+        $("#request-extract-error").html(err).show();
+    }
+    if (isNull(variables) || Object.keys(variables).length === 0) {
+        $("#request-variables").html("");
+        return;
+    }
+    $("#request-variables").html(syntaxHighlight(JSON.stringify(variables, null, 1)));
+}
+
 function syntaxHighlight(json) {
     json = json
         .replace(/&/g, "&amp;")
@@ -379,6 +563,7 @@ function showQueryError(err) {
     $("#query-error").append(tmpl).show();
     $("#query-result").html("").hide();
     $("#time-spent").html("");
+    $("#query-attempts").html("");
     if (err.code_description !== "") {
         $("#badge-result")
             .html(err.code + ": " + err.code_description)
@@ -392,6 +577,7 @@ function showQueryError(err) {
 function hideQueryError() {
     $("#query-result").show();
     $("#time-spent").html("");
+    $("#query-attempts").html("");
     let badge = $("#badge-result");
     let error = $("#query-error");
     if (badge.hasClass("bg-danger") || error.find(".alert-warning")) {
@@ -0,0 +1,61 @@
+export {
+    initResolver,
+    setServerResolver,
+    getServerResolver,
+}
+
+import {isNull} from "./index.js";
+import {selectFile} from "./ssh.js";
+
+function initResolver() {
+    $("#workspace-modal-resolver-type").change(function () {
+        let type = $(this).val();
+        $("#workspaceModal .resolver").hide();
+        $("#workspaceModal .resolver-" + type).show();
+        $("#workspace-modal-resolver-addresses").prop("required", type === "static");
+        $("#workspace-modal-resolver-srv-name").prop("required", type === "dns-srv");
+        $("#workspace-modal-resolver-file").prop("required", type === "file");
+    }).trigger("change");
+
+    $("#workspace-modal-resolver-select-file").click(function () {
+        selectFile($("#workspace-modal-resolver-file"));
+    });
+}
+
+function setServerResolver(resolver) {
+    if (isNull(resolver)) {
+        resolver = {};
+    }
+    $("#workspace-modal-resolver-addresses").val(isNull(resolver.addresses) ? "" : resolver.addresses.join("\n"));
+    $("#workspace-modal-resolver-srv-name").val(resolver.srv_name);
+    $("#workspace-modal-resolver-file").val(resolver.file);
+    $("#workspace-modal-resolver-hosts").val(isNull(resolver.hosts) ? "" : resolver.hosts.join("\n"));
+    $("#workspace-modal-resolver-type").val(isNull(resolver.type) ? "" : resolver.type).trigger("change");
+}
+
+function getServerResolver() {
+    let type = $("#workspace-modal-resolver-type").val();
+    let hosts = getLines("#workspace-modal-resolver-hosts");
+    if (type === "" && hosts.length === 0) {
+        return {};
+    }
+
+    let resolver = {type: type, hosts: hosts};
+    switch (type) {
+        case "static":
+            resolver.addresses = getLines("#workspace-modal-resolver-addresses");
+            break;
+        case "dns-srv":
+            resolver.srv_name = $("#workspace-modal-resolver-srv-name").val().trim();
+            break;
+        case "file":
+            resolver.file = $("#workspace-modal-resolver-file").val().trim();
+            break;
+    }
+
+    return resolver;
+}
+
+function getLines(selector) {
+    return $(selector).val().split("\n").map((s) => s.trim()).filter((s) => s !== "");
+}
@@ -15,9 +15,11 @@ export {
     setRequestTitle,
     setCurrentServer,
     setCurrentQuery,
+    showConnectionState,
+    schemaChanged,
 };
 import {isNull} from "./index.js";
-import {getRequestData, getRequestMetadata, hideQueryError, hideStreamControl, showQueryError} from "./request.js";
+import {getRequestAssertions, getRequestCompression, getRequestData, getRequestExtract, getRequestMetadata, hideQueryError, hideStreamControl, restoreSession, showQueryError} from "./request.js";
 import {WorkspaceTypeQuery} from "./tree.js";
 import {template} from "./template.js";
 
@@ -30,6 +32,7 @@ let currentServices = {};
 let currentRequest = {};
 let currentInputCount = 1;
 let oneOfNodes = new Map();
+let connectionState = undefined;
 
 const protoTypeEnum = "enum";
 const protoTypeBool = "bool";
@@ -55,7 +58,7 @@ const protoType2inputType = {
     message: "checkbox",
 };
 
-function loadServer(srv, show) {
+function loadServer(srv, show, onLoad) {
     currentQuery = undefined;
     currentRequest = {};
 
@@ -83,8 +86,12 @@ function loadServer(srv, show) {
         if (!isNull(message.payload.data.warning)) {
             showServerWarning(message.payload.data.warning);
         }
+        showServerTLS(message.payload.data.tls);
 
         currentServer = message.payload.data.server;
+        if (isNull(connectionState) || connectionState.server_id !== currentServer.id) {
+            hideConnectionState();
+        }
         currentSelectedID = currentServer.id;
         if (srv.type === WorkspaceTypeQuery) {
             currentQuery = message.payload.data.query;
@@ -163,6 +170,9 @@ function loadServer(srv, show) {
                     currentServices[show.service.name],
                     currentServices[show.service.name].methods[show.method.name]
                 );
+                if (onLoad !== undefined) {
+                    onLoad();
+                }
             } else if (
                 message.payload.length > 0 &&
                 !isNull(message.payload[0].methods) &&
@@ -196,6 +206,7 @@ function createRequestForm(service, method) {
 
     setRequestTitle(service.name + "." + method.name);
     enableRequestPanel();
+    restoreSession();
 
     let request = $("#nav-request-data");
     request.html("").hide();
@@ -222,6 +233,9 @@ function createRequestForm(service, method) {
     }
 
     setRequestMetadata(currentRequest);
+    setRequestExtract(currentRequest);
+    setRequestAssertions(currentRequest);
+    $("#request-compression").val(isNull(currentRequest) || isNull(currentRequest.compression) ? "" : currentRequest.compression);
 
     request.show();
 }
@@ -661,6 +675,9 @@ function saveRequest() {
     currentServer.data.request[currentService.name][currentMethod.name] = {
         input: request,
         metadata: metadata,
+        extract: getRequestExtract(),
+        assertions: getRequestAssertions(),
+        compression: getRequestCompression(),
     };
 
     let req = {
@@ -894,6 +911,35 @@ function setRequestMetadata(request) {
     });
 }
 
+function setRequestExtract(request) {
+    let extract = $("#request-extract");
+    extract.val("");
+
+    if (isNull(request) || isNull(request.extract)) {
+        return;
+    }
+
+    extract.val(
+        request.extract
+            .map(function (r) {
+                let path = r.source === "body" ? r.path : r.source + ":" + r.path;
+                return r.variable + " = " + path;
+            })
+            .join("\n")
+    );
+}
+
+function setRequestAssertions(request) {
+    let assertions = $("#request-assertions");
+    assertions.val("");
+
+    if (isNull(request) || isNull(request.assertions)) {
+        return;
+    }
+
+    assertions.val(request.assertions.map((a) => a.expression).join("\n"));
+}
+
 function showServerWarning(warn) {
     let error = $("#query-error");
     for (const w of warn) {
@@ -908,6 +954,95 @@ function showServerWarning(warn) {
     error.show();
 }
 
+function showServerTLS(tls) {
+    let tab = $("#nav-certificate-tab");
+    if (isNull(tls)) {
+        if (tab.hasClass("active")) {
+            $("#nav-result-tab").tab("show");
+        }
+        tab.hide();
+        $("#server-certificate").text("");
+        return;
+    }
+
+    let lines = [
+        tls.version + ", " + tls.cipher_suite + (isNull(tls.alpn) || tls.alpn === "" ? "" : ", ALPN " + tls.alpn),
+        "Server name: " + tls.server_name,
+    ];
+    if (!isNull(tls.warnings)) {
+        lines.push("", "Warnings:");
+        for (const w of tls.warnings) {
+            lines.push("  " + w);
+        }
+    }
+    tls.certificates.forEach(function (cert, idx) {
+        let sans = (cert.dns_names || []).concat(cert.ip_addresses || []);
+        lines.push(
+            "",
+            "#" + idx + (cert.is_ca ? " (CA)" : ""),
+            "  Subject:     " + cert.subject,
+            "  Issuer:      " + cert.issuer,
+            "  SANs:        " + (sans.length > 0 ? sans.join(", ") : "-"),
+            "  Not before:  " + new Date(cert.not_before).toLocaleString(),
+            "  Not after:   " + new Date(cert.not_after).toLocaleString(),
+            "  Serial:      " + cert.serial,
+            "  SHA-256:     " + cert.fingerprint,
+        );
+    });
+
+    $("#server-certificate").text(lines.join("\n"));
+    tab.show();
+}
+
+// schemaChanged loads the current server again when its services have changed.
+function schemaChanged(data) {
+    if (isNull(currentServer) || currentServer.id !== data.server_id) {
+        return;
+    }
+
+    saveRequest();
+
+    if (!isNull(currentQuery)) {
+        loadServer({id: currentQuery.id, type: WorkspaceTypeQuery});
+        return;
+    }
+
+    let show = undefined;
+    if (!isNull(currentService) && !isNull(currentMethod)) {
+        show = {
+            service: {name: currentService.name},
+            method: {name: currentMethod.name},
+        };
+    }
+    loadServer({id: currentServer.id}, show);
+}
+
+function showConnectionState(state) {
+    connectionState = state;
+
+    let labels = {
+        IDLE: "Idle",
+        CONNECTING: "Connecting",
+        READY: "Connected",
+        TRANSIENT_FAILURE: "Connection failed",
+    };
+    let title = state.state + " since " + new Date(state.time).toLocaleTimeString();
+    if (!isNull(state.error) && state.error !== "") {
+        title += "\n" + state.error;
+    }
+
+    let el = $("#connection-state");
+    el.removeClass().addClass("state-" + state.state.toLowerCase()).attr("title", title);
+    el.find(".state").text(isNull(labels[state.state]) ? state.state : labels[state.state]);
+    el.find(".error").text(isNull(state.error) ? "" : state.error);
+    el.show();
+}
+
+function hideConnectionState() {
+    connectionState = undefined;
+    $("#connection-state").hide();
+}
+
 function setCurrentServer(s) {
     currentServer = s;
 }
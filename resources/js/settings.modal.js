@@ -1,4 +1,5 @@
 import { currentSettings, isNull, setCurrentSettings } from "./index.js";
+import { getCallOptions, setCallOptions } from "./call.js";
 
 export { initSettingsModal, showSettingsModal };
 
@@ -22,6 +23,12 @@ function initSettingsModal() {
     );
   });
 
+  $("#settings-modal-form-proxy-type").on("change", function () {
+    let enabled = $(this).val() !== "none";
+    $("#settings-modal-form .proxy").attr("disabled", !enabled);
+    $("#settings-modal-form-proxy-addr").prop("required", enabled);
+  });
+
   $("#settings-modal-form-single-instance").on("change", function () {
     if (currentSettings.single_instance !== $(this).is(":checked")) {
       $("#settings-modal-form-single-instance-restart").css(
@@ -57,15 +64,35 @@ function saveSettings() {
         $("#settings-modal-form-max-loop-depth").val(),
         10
       ),
+      cert_expiry_warning_days: parseInt(
+        $("#settings-modal-form-cert-expiry-warning-days").val(),
+        10
+      ),
       non_blocking_connection: $(
         "#settings-modal-form-non-blocking-connection"
       ).is(":checked"),
+      auto_reconnect: $("#settings-modal-form-auto-reconnect").is(":checked"),
+      reconnect_base_delay: parseInt(
+        $("#settings-modal-form-reconnect-base-delay").val(),
+        10
+      ),
+      reconnect_max_delay: parseInt(
+        $("#settings-modal-form-reconnect-max-delay").val(),
+        10
+      ),
       sort_methods_by_name: $("#settings-modal-form-sort-methods-by-name").is(
         ":checked"
       ),
       single_instance: $("#settings-modal-form-single-instance").is(":checked"),
       emit_defaults: $("#settings-modal-form-emit-defaults").is(":checked"),
       check_updates: $("#settings-modal-form-check-updates").is(":checked"),
+      proxy: {
+        type: $("#settings-modal-form-proxy-type").val(),
+        addr: $("#settings-modal-form-proxy-addr").val(),
+        login: $("#settings-modal-form-proxy-login").val(),
+        password: $("#settings-modal-form-proxy-password").val(),
+      },
+      call_options: getCallOptions("settings-modal-form-call"),
     },
   };
   astilectron.sendMessage(req, function (message) {
@@ -92,10 +119,23 @@ function showSettingsModal() {
       currentSettings.k8s_request_timeout
   );
   $("#settings-modal-form-max-loop-depth").val(currentSettings.max_loop_depth);
+  $("#settings-modal-form-cert-expiry-warning-days").val(
+      currentSettings.cert_expiry_warning_days
+  );
   $("#settings-modal-form-non-blocking-connection").prop(
     "checked",
     currentSettings.non_blocking_connection
   );
+  $("#settings-modal-form-auto-reconnect").prop(
+    "checked",
+    currentSettings.auto_reconnect
+  );
+  $("#settings-modal-form-reconnect-base-delay").val(
+    currentSettings.reconnect_base_delay
+  );
+  $("#settings-modal-form-reconnect-max-delay").val(
+    currentSettings.reconnect_max_delay
+  );
   $("#settings-modal-form-sort-methods-by-name").prop(
     "checked",
     currentSettings.sort_methods_by_name
@@ -112,5 +152,13 @@ function showSettingsModal() {
       "checked",
       currentSettings.check_updates
   );
+  let proxy = isNull(currentSettings.proxy) ? {} : currentSettings.proxy;
+  $("#settings-modal-form-proxy-addr").val(proxy.addr);
+  $("#settings-modal-form-proxy-login").val(proxy.login);
+  $("#settings-modal-form-proxy-password").val(proxy.password);
+  $("#settings-modal-form-proxy-type")
+    .val(isNull(proxy.type) ? "none" : proxy.type)
+    .trigger("change");
+  setCallOptions("settings-modal-form-call", currentSettings.call_options);
   $("#settingsModal").modal("show");
 }
@@ -0,0 +1,75 @@
+export {
+    initSSH,
+    setServerSSH,
+    getServerSSH,
+    selectFile,
+}
+
+import {isNull} from "./index.js";
+
+function initSSH() {
+    $("#workspace-modal-ssh-enabled").change(function () {
+        $("#nav-workspace-ssh .ssh").attr("disabled", !$(this).is(":checked"));
+    });
+
+    $("#ssh-private-key-select-file").click(function () {
+        selectFile($("#ssh-private-key-file-path"));
+    });
+
+    $("#ssh-known-hosts-select-file").click(function () {
+        selectFile($("#ssh-known-hosts-file-path"));
+    });
+}
+
+function setServerSSH(ssh) {
+    let enabled = $("#workspace-modal-ssh-enabled");
+
+    if (isNull(ssh) || isNull(ssh.enabled) || !ssh.enabled) {
+        enabled.prop("checked", false).trigger('change');
+        return;
+    }
+
+    enabled.prop("checked", true).trigger('change');
+
+    $("#ssh-host").val(ssh.host);
+    $("#ssh-user").val(ssh.user);
+    if (!isNull(ssh.local_port) && ssh.local_port > 0) {
+        $("#ssh-local-port").val(ssh.local_port);
+    }
+    $("#ssh-remote-addr").val(ssh.remote_addr);
+    $("#ssh-private-key-file-path").val(ssh.private_key_file);
+    $("#ssh-passphrase").val(ssh.passphrase);
+    $("#ssh-use-agent").prop("checked", ssh.use_agent === true);
+    $("#ssh-known-hosts-file-path").val(ssh.known_hosts_file);
+    $("#ssh-insecure-ignore-host-key").prop("checked", ssh.insecure_ignore_host_key === true);
+}
+
+function getServerSSH() {
+    if (!$("#workspace-modal-ssh-enabled").is(":checked")) {
+        return {};
+    }
+
+    return {
+        enabled: true,
+        host: $("#ssh-host").val(),
+        user: $("#ssh-user").val(),
+        local_port: $("#ssh-local-port").val(),
+        remote_addr: $("#ssh-remote-addr").val(),
+        private_key_file: $("#ssh-private-key-file-path").val(),
+        passphrase: $("#ssh-passphrase").val(),
+        use_agent: $("#ssh-use-agent").is(":checked"),
+        known_hosts_file: $("#ssh-known-hosts-file-path").val(),
+        insecure_ignore_host_key: $("#ssh-insecure-ignore-host-key").is(":checked"),
+    };
+}
+
+function selectFile(input) {
+    const {dialog} = require("electron").remote;
+    let files = dialog.showOpenDialogSync({
+        properties: ["openFile", "showHiddenFiles"],
+    });
+    if (files === undefined) {
+        return;
+    }
+    input.val(files[0]);
+}
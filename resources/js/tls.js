@@ -0,0 +1,104 @@
+export {
+    initTLS,
+    setServerTLS,
+    getServerTLS,
+}
+
+import {isNull} from "./index.js";
+import {selectFile} from "./ssh.js";
+
+const fileInputs = ["root-certificate", "client-certificate", "client-key", "pkcs12"];
+
+function initTLS() {
+    for (const name of fileInputs) {
+        $("#workspace-modal-" + name + "-select-file").click(function () {
+            selectFile($("#workspace-modal-" + name + "-path"));
+        });
+    }
+
+    $("#certgen-select-dir").click(function () {
+        selectDir($("#certgen-dir"));
+    });
+
+    $("#certgen-generate").click(function () {
+        generateCertificates();
+    });
+}
+
+function setServerTLS(data) {
+    $("#workspace-modal-use-system-roots").prop("checked", data.use_system_roots);
+    $("#workspace-modal-root-certificate-path").val(data.root_certificate_path);
+    $("#workspace-modal-client-certificate-path").val(data.client_certificate_path);
+    $("#workspace-modal-client-key-path").val(data.client_key_path);
+    $("#workspace-modal-pkcs12-path").val(data.pkcs12_path);
+    $("#workspace-modal-pkcs12-password").val(data.pkcs12_password);
+    $("#workspace-modal-min-tls-version").val(isNull(data.min_tls_version) ? "" : data.min_tls_version);
+    $("#workspace-modal-max-tls-version").val(isNull(data.max_tls_version) ? "" : data.max_tls_version);
+    $("#workspace-modal-cipher-suites").val(isNull(data.cipher_suites) ? "" : data.cipher_suites.join(", "));
+    $("#workspace-modal-alpn").val(isNull(data.alpn) ? "" : data.alpn.join(", "));
+}
+
+function getServerTLS() {
+    return {
+        use_system_roots: $("#workspace-modal-use-system-roots").is(":checked"),
+        root_certificate_path: $("#workspace-modal-root-certificate-path").val(),
+        client_certificate_path: $("#workspace-modal-client-certificate-path").val(),
+        client_key_path: $("#workspace-modal-client-key-path").val(),
+        pkcs12_path: $("#workspace-modal-pkcs12-path").val(),
+        pkcs12_password: $("#workspace-modal-pkcs12-password").val(),
+        min_tls_version: $("#workspace-modal-min-tls-version").val(),
+        max_tls_version: $("#workspace-modal-max-tls-version").val(),
+        cipher_suites: splitList($("#workspace-modal-cipher-suites").val()),
+        alpn: splitList($("#workspace-modal-alpn").val()),
+    };
+}
+
+function splitList(value) {
+    return value.split(",").map(v => v.trim()).filter(v => v !== "");
+}
+
+function selectDir(input) {
+    const {dialog} = require("electron").remote;
+    let dirs = dialog.showOpenDialogSync({
+        properties: ["openDirectory", "createDirectory", "showHiddenFiles"],
+    });
+    if (dirs === undefined) {
+        return;
+    }
+    input.val(dirs[0]);
+}
+
+function generateCertificates() {
+    let req = {
+        name: "certificates.generate",
+        payload: {
+            dir: $("#certgen-dir").val(),
+            key_type: $("#certgen-key-type").val(),
+            hosts: splitList($("#certgen-hosts").val()),
+            validity_days: parseInt($("#certgen-validity-days").val(), 10),
+        },
+    };
+
+    let result = $("#certgen-result");
+    astilectron.sendMessage(req, function (message) {
+        if (message.payload.status !== "ok") {
+            result.addClass("text-danger").text(message.payload.error.message);
+            return;
+        }
+
+        let data = message.payload.data;
+        $("#workspace-modal-use-plain-text").prop("checked", false).trigger("change");
+        $("#workspace-modal-root-certificate").val(data.root_certificate);
+        $("#workspace-modal-client-certificate").val(data.client_certificate);
+        $("#workspace-modal-client-key").val(data.client_key);
+        $("#workspace-modal-client-certificate-path").val("");
+        $("#workspace-modal-client-key-path").val("");
+        $("#workspace-modal-pkcs12-path").val("");
+        $("#workspace-modal-pkcs12-password").val("");
+
+        result.removeClass("text-danger").text(
+            "Saved to " + Object.values(data.files).sort().join(", ") +
+            ". The CA and the client certificate are set on this server, save it to apply."
+        );
+    });
+}
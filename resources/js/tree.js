@@ -13,6 +13,7 @@ import {
 } from "./server.js";
 import { dataIdToNode, isNull, treeRootNodes } from "./index.js";
 import { editServer } from "./workspace.modal.js";
+import { workspaceExport } from "./workspace.export.js";
 
 const WorkspaceTypeFolder = "f";
 const WorkspaceTypeServer = "s";
@@ -76,6 +77,9 @@ function initTreeDrag() {
       case "duplicate":
         treeMenuDuplicate(node);
         break;
+      case "export":
+        workspaceExport(node.data.id);
+        break;
     }
   });
 
@@ -288,6 +292,13 @@ function getTreeDropdown(node) {
             '" data-action="rename-folder"><i class="bi bi-pen"></i> Rename</a></li>'
         )
       );
+      menu.append(
+        $(
+          '<li><a class="dropdown-item" data-id="' +
+            node.data.id +
+            '" data-action="export"><i class="bi bi-box-arrow-up"></i> Export</a></li>'
+        )
+      );
       menu.append(
         $(
           '<li><a class="dropdown-item' +
@@ -313,6 +324,13 @@ function getTreeDropdown(node) {
               '" data-action="duplicate"><i class="bi bi bi-files"></i> Duplicate</a></li>'
           )
       );
+      menu.append(
+        $(
+          '<li><a class="dropdown-item" data-id="' +
+            node.data.id +
+            '" data-action="export"><i class="bi bi-box-arrow-up"></i> Export</a></li>'
+        )
+      );
       menu.append(
         $(
           '<li><a class="dropdown-item' +
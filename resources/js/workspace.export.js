@@ -4,9 +4,22 @@ export {
 };
 
 import {showModalError} from "./index.js";
+import {showTree} from "./tree.js";
 
-function workspaceExport() {
+function workspaceExport(id = null) {
     const {dialog} = require("electron").remote;
+    const redactModes = ["none", "placeholder", "strip"];
+    let mode = dialog.showMessageBoxSync({
+        type: "question",
+        message: "Export credentials?",
+        detail: "Passwords, tokens, secrets and private keys can be replaced with placeholders or removed, so the export can be shared safely.",
+        buttons: ["Keep credentials", "Replace with placeholders", "Remove credentials", "Cancel"],
+        cancelId: 3,
+    });
+    if (mode >= redactModes.length) {
+        return;
+    }
+
     let path = dialog.showSaveDialogSync({
         defaultPath: "warthog-export.json",
         properties: ["showHiddenFiles"],
@@ -16,7 +29,7 @@ function workspaceExport() {
     }
 
     astilectron.sendMessage(
-        {name: "workspace.export.file", payload: {path: path}},
+        {name: "workspace.export.file", payload: {path: path, id: id, redact: redactModes[mode]}},
         function (message) {
             if (message.payload.status === "ok") {
                 return;
@@ -26,7 +39,7 @@ function workspaceExport() {
     );
 }
 
-function workspaceImport() {
+function workspaceImport(strategy = "rename") {
     const {dialog} = require("electron").remote;
     let path = dialog.showOpenDialogSync({
         defaultPath: "warthog-export.json",
@@ -37,9 +50,10 @@ function workspaceImport() {
     }
 
     astilectron.sendMessage(
-        {name: "workspace.import.file", payload: {path: path[0]}},
+        {name: "workspace.import.file", payload: {path: path[0], strategy: strategy}},
         function (message) {
             if (message.payload.status === "ok") {
+                showTree(message.payload.data.tree);
                 return;
             }
             showModalError(message);
@@ -4,6 +4,11 @@ import {isNull, modalTreeExpandedNodes} from "./index.js";
 import {loadServer} from "./server.js";
 import {getServerAuth, initAuth, setServerAuth, validateAuthJWTPayload} from "./auth.js";
 import {getServerK8S, initK8S, setServerK8S} from "./k8s.js";
+import {getServerSSH, initSSH, setServerSSH} from "./ssh.js";
+import {getServerTLS, initTLS, setServerTLS} from "./tls.js";
+import {getCallOptions, setCallOptions} from "./call.js";
+import {getServerResolver, initResolver, setServerResolver} from "./resolver.js";
+import {loadEnvironments} from "./environment.modal.js";
 
 function initWorkspaceModal() {
     let workspaceModal = document.getElementById("workspaceModal");
@@ -24,6 +29,17 @@ function initWorkspaceModal() {
                 .css("visibility", "visible");
         }
 
+        let environmentID = $("#workspace-modal-environment").data("environment-id");
+        loadEnvironments(function (environments) {
+            let sel = $("#workspace-modal-environment");
+            sel.empty();
+            sel.append($("<option>", {value: 0, text: "None"}));
+            for (const env of environments) {
+                sel.append($("<option>", {value: env.id, text: env.name}));
+            }
+            sel.val(isNull(environmentID) ? 0 : environmentID);
+        });
+
         astilectron.sendMessage(req, function (message) {
             let tree = $("#workspace-modal-tree");
             let selectedNode = undefined;
@@ -67,15 +83,22 @@ function initWorkspaceModal() {
             $("#workspaceModalLabel").html("New workspace");
             $("#workspace-modal-server-id").val("");
             $("#workspace-modal-folder-id").val("");
+            $("#workspace-modal-environment").removeData("environment-id");
             $("#workspace-modal-badge-server-id").css("visibility", "hidden");
             $("#workspace-modal-basic-form").removeClass("was-validated");
             $("#workspace-modal-tls-form").removeClass("was-validated");
             $("#workspace-modal-k8s-form").removeClass("was-validated");
+            $("#workspace-modal-ssh-form").removeClass("was-validated");
             $("#workspaceModal .proto-files").attr("disabled", false);
             $("#workspaceModal .ssl-certificate").attr("disabled", false);
+            $("#workspace-modal-transport").val("grpc").trigger("change");
+            $("#workspace-modal-proxy-type").val("").trigger("change");
+            setServerResolver({});
+            $("#workspace-modal-connection-form").removeClass("was-validated");
             $('#authentication-type').val("none").trigger('change');
             $("#workspace-modal-k8s-enabled").prop("checked", false).trigger('change');
             $("#workspace-modal-k8s-gcs-enabled").prop("checked", false).trigger('change');
+            $("#workspace-modal-ssh-enabled").prop("checked", false).trigger('change');
         });
     });
 
@@ -87,10 +110,17 @@ function initWorkspaceModal() {
         let basicForm = $("#workspace-modal-basic-form")[0];
         let tlsForm = $("#workspace-modal-tls-form")[0];
         let k8sForm = $("#workspace-modal-k8s-form")[0];
+        let sshForm = $("#workspace-modal-ssh-form")[0];
+        let connectionForm = $("#workspace-modal-connection-form")[0];
+        validateAddr();
         if (!basicForm.checkValidity()) {
             event.preventDefault();
             event.stopPropagation();
             $("#nav-workspace-modal-basic-tab").tab("show");
+        } else if (!connectionForm.checkValidity()) {
+            event.preventDefault();
+            event.stopPropagation();
+            $("#nav-workspace-modal-connection-tab").tab("show");
         } else if (!tlsForm.checkValidity()) {
             event.preventDefault();
             event.stopPropagation();
@@ -99,6 +129,10 @@ function initWorkspaceModal() {
             event.preventDefault();
             event.stopPropagation();
             $("#nav-workspace-modal-k8s-tab").tab("show");
+        } else if ($("#workspace-modal-ssh-enabled").is(":checked") && !validateSSH(sshForm)) {
+            event.preventDefault();
+            event.stopPropagation();
+            $("#nav-workspace-modal-ssh-tab").tab("show");
         } else if (!validateAuthJWTPayload()) {
             $("#nav-workspace-authentication-tab").tab("show");
         } else {
@@ -107,8 +141,20 @@ function initWorkspaceModal() {
         basicForm.classList.add("was-validated");
         tlsForm.classList.add("was-validated");
         k8sForm.classList.add("was-validated");
+        sshForm.classList.add("was-validated");
+        connectionForm.classList.add("was-validated");
     });
 
+    $("#workspace-modal-transport").change(function () {
+        $("#workspace-modal-http2").attr("disabled", $(this).val() === "grpc");
+    }).trigger("change");
+
+    $("#workspace-modal-proxy-type").change(function () {
+        let enabled = $(this).val() === "http" || $(this).val() === "socks5";
+        $("#workspaceModal .proxy").attr("disabled", !enabled);
+        $("#workspace-modal-proxy-addr").prop("required", enabled);
+    }).trigger("change");
+
     $("#workspace-modal-add-proto-files").click(function () {
         addProtoFiles();
     });
@@ -146,6 +192,9 @@ function initWorkspaceModal() {
 
     initAuth();
     initK8S();
+    initSSH();
+    initTLS();
+    initResolver();
 }
 
 function createFolder() {
@@ -224,6 +273,12 @@ function createWorkspace() {
     let rootCertificate = $("#workspace-modal-root-certificate").val();
     let clientCertificate = $("#workspace-modal-client-certificate").val();
     let clientKey = $("#workspace-modal-client-key").val();
+    let environmentID = parseInt($("#workspace-modal-environment").val(), 10);
+    let transport = $("#workspace-modal-transport").val();
+    let http2 = $("#workspace-modal-http2").is(":checked");
+    let authority = $("#workspace-modal-authority").val();
+    let serverName = $("#workspace-modal-server-name").val();
+    let userAgent = $("#workspace-modal-user-agent").val();
 
     let protoFiles = [],
         importPath = [];
@@ -250,8 +305,20 @@ function createWorkspace() {
             root_certificate: rootCertificate,
             client_certificate: clientCertificate,
             client_key: clientKey,
+            transport: transport,
+            http2: http2,
+            authority: authority,
+            server_name: serverName,
+            user_agent: userAgent,
+            environment_id: isNaN(environmentID) ? 0 : environmentID,
             auth: getServerAuth(),
             k8s: getServerK8S(),
+            ssh: getServerSSH(),
+            proxy: getServerProxy(),
+            call_options: getCallOptions("workspace-modal-call"),
+            service_config: $("#workspace-modal-service-config").val(),
+            resolver: getServerResolver(),
+            ...getServerTLS(),
         },
     };
 
@@ -270,6 +337,39 @@ function createWorkspace() {
     });
 }
 
+function validateAddr() {
+    let addr = $("#workspace-modal-grpc-addr");
+    let forwarding = $("#workspace-modal-k8s-enabled").is(":checked") || $("#workspace-modal-ssh-enabled").is(":checked");
+    if (forwarding && /^unix(-abstract)?:/.test(addr.val())) {
+        addr[0].setCustomValidity("port forwarding requires the host:port address");
+    } else {
+        addr[0].setCustomValidity("");
+    }
+}
+
+function validateSSH(form) {
+    let keyFile = $("#ssh-private-key-file-path");
+    if (keyFile.val() === "" && !$("#ssh-use-agent").is(":checked")) {
+        keyFile[0].setCustomValidity("private key file or SSH agent is required");
+    } else {
+        keyFile[0].setCustomValidity("");
+    }
+    return form.checkValidity();
+}
+
+function getServerProxy() {
+    let type = $("#workspace-modal-proxy-type").val();
+    if (type === "") {
+        return {};
+    }
+    return {
+        type: type,
+        addr: $("#workspace-modal-proxy-addr").val(),
+        login: $("#workspace-modal-proxy-login").val(),
+        password: $("#workspace-modal-proxy-password").val(),
+    };
+}
+
 function addProtoFiles() {
     const {dialog} = require("electron").remote;
     let files = dialog.showOpenDialogSync({
@@ -351,6 +451,22 @@ function editServer(srv) {
     $("#workspace-modal-root-certificate").val(srv.data.root_certificate);
     $("#workspace-modal-client-certificate").val(srv.data.client_certificate);
     $("#workspace-modal-client-key").val(srv.data.client_key);
+    $("#workspace-modal-transport").val(isNull(srv.data.transport) ? "grpc" : srv.data.transport).trigger("change");
+    $("#workspace-modal-http2").prop("checked", srv.data.http2);
+    $("#workspace-modal-authority").val(srv.data.authority);
+    $("#workspace-modal-server-name").val(srv.data.server_name);
+    setServerTLS(srv.data);
+    $("#workspace-modal-user-agent").val(srv.data.user_agent);
+    if (!isNull(srv.data.proxy)) {
+        $("#workspace-modal-proxy-addr").val(srv.data.proxy.addr);
+        $("#workspace-modal-proxy-login").val(srv.data.proxy.login);
+        $("#workspace-modal-proxy-password").val(srv.data.proxy.password);
+        $("#workspace-modal-proxy-type").val(isNull(srv.data.proxy.type) ? "" : srv.data.proxy.type).trigger("change");
+    }
+    setCallOptions("workspace-modal-call", srv.data.call_options);
+    $("#workspace-modal-service-config").val(srv.data.service_config);
+    setServerResolver(srv.data.resolver);
+    $("#workspace-modal-environment").data("environment-id", srv.data.environment_id);
 
     if (srv.data.use_reflection) {
         $("#workspaceModal .proto-files").attr("disabled", true);
@@ -390,6 +506,7 @@ function editServer(srv) {
     if (!isNull(srv.data.k8s)) {
         setServerK8S(srv.data.k8s);
     }
+    setServerSSH(srv.data.ssh);
 
     $("#workspaceModal").modal("show");
 }